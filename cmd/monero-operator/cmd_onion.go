@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cirocosta/monero-operator/pkg/tor"
+)
+
+type OnionCommand struct {
+	Prefix    string        `long:"prefix" required:"true" description:"base32 (a-z, 2-7) prefix that the onion address should start with"`
+	Timeout   time.Duration `long:"timeout" default:"1h" description:"how long to search for before giving up"`
+	Workers   int           `long:"workers" description:"number of goroutines to search with (defaults to all CPUs)"`
+	Directory string        `long:"directory" description:"hidden service directory to write the keys and hostname to, rather than printing a Secret"`
+	Name      string        `long:"name" default:"onion" description:"name of the Secret to print"`
+	Namespace string        `long:"namespace" description:"namespace of the Secret to print"`
+}
+
+func (c *OnionCommand) Execute(_ []string) error {
+	if err := tor.ValidatePrefix(c.Prefix); err != nil {
+		return fmt.Errorf("validate prefix: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(signals.SetupSignalHandler(), c.Timeout)
+	defer cancel()
+
+	rate := tor.MeasureKeyRate(ctx, time.Second, c.Workers)
+	fmt.Fprintf(os.Stderr, "searching for '%s' at %.0f keys/s, expected to take %s\n",
+		c.Prefix, rate, tor.ExpectedSearchTime(c.Prefix, rate),
+	)
+
+	creds, err := tor.GenerateVanityCredentials(ctx, c.Prefix, c.Workers)
+	if err != nil {
+		return fmt.Errorf("generate vanity credentials: %w", err)
+	}
+
+	fmt.Fprintln(os.Stderr, creds.Hostname())
+
+	if c.Directory != "" {
+		if err := c.WriteDirectory(creds); err != nil {
+			return fmt.Errorf("write directory: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := c.WriteSecret(creds); err != nil {
+		return fmt.Errorf("write secret: %w", err)
+	}
+
+	return nil
+}
+
+// WriteDirectory writes the credentials in the layout of a hidden service
+// directory, as tor would.
+//
+func (c *OnionCommand) WriteDirectory(creds tor.Credentials) error {
+	if err := os.MkdirAll(c.Directory, 0700); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", c.Directory, err)
+	}
+
+	for filename, content := range creds {
+		fpath := filepath.Join(c.Directory, filename)
+		if err := os.WriteFile(fpath, content, 0600); err != nil {
+			return fmt.Errorf("write '%s': %w", fpath, err)
+		}
+	}
+
+	return nil
+}
+
+// WriteSecret prints a Secret holding the credentials, ready to be
+// referenced by a MoneroNodeSet (`tor.secretRef`).
+//
+func (c *OnionCommand) WriteSecret(creds tor.Credentials) error {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.Name,
+			Namespace: c.Namespace,
+		},
+		Data: creds,
+	}
+
+	b, err := yaml.Marshal(secret)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	if _, err := os.Stdout.Write(b); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	parser.AddCommand("onion",
+		"Generate a vanity onion address",
+		"Search for a hidden service identity whose onion address starts with a given prefix",
+		&OnionCommand{},
+	)
+}
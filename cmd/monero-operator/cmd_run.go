@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -9,10 +11,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
+	"github.com/cirocosta/monero-operator/pkg/metrics"
 	"github.com/cirocosta/monero-operator/pkg/reconciler"
 )
 
-type RunCommand struct{}
+type RunCommand struct {
+	MetricsAddress string `long:"metrics-address" default:":9000" description:"address to serve prometheus metrics on"`
+}
 
 func (c *RunCommand) Execute(_ []string) error {
 	scheme := runtime.NewScheme()
@@ -43,6 +48,21 @@ func (c *RunCommand) Execute(_ []string) error {
 		return fmt.Errorf("register reconcilers: %w", err)
 	}
 
+	exporter := metrics.NewExporter(
+		metrics.WithListenAddress(c.MetricsAddress),
+	)
+	defer exporter.Close()
+
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if err := exporter.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			return fmt.Errorf("exporter run: %w", err)
+		}
+
+		return nil
+	})); err != nil {
+		return fmt.Errorf("add exporter: %w", err)
+	}
+
 	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
 		return fmt.Errorf("mgr start: %w", err)
 	}
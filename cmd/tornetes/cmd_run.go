@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -11,11 +12,18 @@ import (
 	"path/filepath"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cirocosta/monero-operator/pkg/metrics"
 )
 
 type RunCommand struct {
 	Source      string `long:"source" required:"true" description:"source of creds, cfg, etc"`
 	Destination string `long:"destination" required:"true" description:"where to save files to"`
+
+	MetricsAddress  string `long:"metrics-address" description:"address to serve prometheus metrics on (disabled if empty)"`
+	ControlAddress  string `long:"control-address" default:"127.0.0.1:9051" description:"address of tor's control port to gather metrics from"`
+	ControlPassword string `long:"control-password" env:"TOR_CONTROL_PASSWORD" description:"password to authenticate against tor's control port (cookie or none, if empty)"`
 }
 
 func init() {
@@ -48,6 +56,16 @@ func (c *RunCommand) Execute(_ []string) error {
 
 	defer cmd.Process.Kill()
 
+	if c.MetricsAddress != "" {
+		metricsC := c.serveMetrics(ctx)
+
+		go func() {
+			if err := <-metricsC; err != nil {
+				fmt.Fprintf(os.Stderr, "metrics: %v\n", err)
+			}
+		}()
+	}
+
 	watchC, err := watchFs(ctx, c.Source)
 	if err != nil {
 		return fmt.Errorf("watchfs: %w", err)
@@ -70,6 +88,41 @@ func (c *RunCommand) Execute(_ []string) error {
 	return nil
 }
 
+// serveMetrics serves prometheus metrics about tor, gathered through its
+// control port, notifying a channel if that stops.
+//
+func (c *RunCommand) serveMetrics(ctx context.Context) chan error {
+	var opts []metrics.TorCollectorOption
+	if c.ControlPassword != "" {
+		opts = append(opts, metrics.WithControlPassword(c.ControlPassword))
+	}
+
+	collector := metrics.NewTorCollector("tcp", c.ControlAddress, opts...)
+	prometheus.MustRegister(collector)
+
+	exporter := metrics.NewExporter(
+		metrics.WithListenAddress(c.MetricsAddress),
+	)
+
+	errC := make(chan error, 2)
+
+	go func() {
+		if err := collector.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			errC <- fmt.Errorf("collector run: %w", err)
+		}
+	}()
+
+	go func() {
+		defer exporter.Close()
+
+		if err := exporter.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			errC <- fmt.Errorf("exporter run: %w", err)
+		}
+	}()
+
+	return errC
+}
+
 // watchFs watches fpaths and on any change (or errors), notifies a channel.
 //
 func watchFs(ctx context.Context, fpaths ...string) (chan error, error) {
@@ -81,6 +134,7 @@ func watchFs(ctx context.Context, fpaths ...string) (chan error, error) {
 	done := make(chan error)
 	go func() {
 		defer close(done)
+		defer watcher.Close()
 
 		for {
 			select {
@@ -101,15 +155,10 @@ func watchFs(ctx context.Context, fpaths ...string) (chan error, error) {
 				return
 
 			case <-ctx.Done():
-				if ctx.Err() != nil {
-					done <- fmt.Errorf("context err: %w", err)
-				}
+				done <- fmt.Errorf("context err: %w", ctx.Err())
 				return
 			}
 		}
-
-		close(done)
-		watcher.Close()
 	}()
 
 	for _, fpath := range fpaths {
@@ -3,6 +3,7 @@ module github.com/cirocosta/monero-operator
 go 1.16
 
 require (
+	filippo.io/edwards25519 v1.0.0-beta.2
 	github.com/cirocosta/go-monero v0.0.0-20210522223237-fd03dfddd5ca
 	github.com/fsnotify/fsnotify v1.4.9
 	github.com/go-logr/logr v0.4.0
@@ -16,4 +17,5 @@ require (
 	k8s.io/client-go v0.20.2
 	k8s.io/utils v0.0.0-20210111153108-fddb29f9d009
 	sigs.k8s.io/controller-runtime v0.8.3
+	sigs.k8s.io/yaml v1.2.0
 )
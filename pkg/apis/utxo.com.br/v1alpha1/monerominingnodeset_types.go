@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -8,6 +10,12 @@ import (
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories=monero
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type==\"Ready\")].status`
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.replicas`
+// +kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.readyReplicas`
+// +kubebuilder:printcolumn:name="Hashrate",type=integer,JSONPath=`.status.hashrate.sixtySeconds`
+// +kubebuilder:printcolumn:name="Pool",type=string,JSONPath=`.status.activePool`,priority=1
+// +kubebuilder:printcolumn:name="Upstreams",type=integer,JSONPath=`.status.proxy.activeUpstreams`,priority=1
+// +kubebuilder:printcolumn:name="Scheduled",type=boolean,JSONPath=`.status.schedule.active`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 type MoneroMiningNodeSet struct {
@@ -24,16 +32,490 @@ type MoneroMiningNodeSetSpec struct {
 	HardAntiAffinity bool   `json:"hardAntiAffinity,omitempty"`
 
 	Xmrig XmrigConfig `json:"xmrig,omitempty"`
+
+	// TopologySpreadConstraints spreads the miners evenly across hosts,
+	// zones, or any other topology domain.
+	//
+	TopologySpreadConstraints []MoneroTopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Solo makes the miners mine directly against a MoneroNodeSet (xmrig
+	// in daemon mode) rather than through the pools in the xmrig config.
+	//
+	Solo *MoneroMiningSoloConfig `json:"solo,omitempty"`
+
+	// P2PoolRef makes the miners mine through a MoneroP2Pool (replacing
+	// the pools in the xmrig config).
+	//
+	P2PoolRef *MoneroP2PoolReference `json:"p2poolRef,omitempty"`
+
+	// Pools is a list of pools to mine on, in order of preference. Each
+	// one gets probed periodically, with the miners being moved off the
+	// unhealthy ones (and back once they recover).
+	//
+	// When set, it replaces the pools in the xmrig config.
+	//
+	Pools []MoneroMiningPool `json:"pools,omitempty"`
+
+	// Schedule restricts mining to windows of time, with the miners being
+	// scaled down to zero outside of them.
+	//
+	Schedule *MoneroMiningSchedule `json:"schedule,omitempty"`
+
+	// Performance tunes the miners (and the nodes they run on) for
+	// RandomX.
+	//
+	Performance *MoneroMiningPerformance `json:"performance,omitempty"`
+
+	// Budget caps the CPU that the set as a whole gets to use.
+	//
+	Budget *MoneroMiningBudget `json:"budget,omitempty"`
+
+	// XmrigProxy deploys xmrig-proxy in front of the miners, having it
+	// hold the connections to the pools rather than each miner doing so.
+	//
+	XmrigProxy *MoneroMiningXmrigProxy `json:"xmrigProxy,omitempty"`
+}
+
+const (
+	XmrigProxyModeNicehash = "nicehash"
+	XmrigProxyModeSimple   = "simple"
+
+	DefaultXmrigProxyImage = "index.docker.io/utxobr/xmrig-proxy:v6.4.0"
+)
+
+type MoneroMiningXmrigProxy struct {
+	//+kubebuilder:default="index.docker.io/utxobr/xmrig-proxy:v6.4.0"
+	Image string `json:"image,omitempty"`
+
+	// Replicas is the number of proxies that miners get load balanced
+	// across, each one holding its own upstream connections.
+	//
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default=1
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Mode is how miners are multiplexed over upstream connections:
+	// `nicehash` fitting up to 256 miners in each, `simple` using one
+	// upstream connection per miner (but still behind the proxy's IP).
+	//
+	//+kubebuilder:validation:Enum=nicehash;simple
+	//+kubebuilder:default=nicehash
+	Mode string `json:"mode,omitempty"`
+
+	// Args are extra arguments passed to xmrig-proxy.
+	//
+	Args []string `json:"args,omitempty"`
+}
+
+type MoneroMiningBudget struct {
+	// CPU is the total number of cores (e.g., `12` or `6500m`) that the
+	// miners get to use, split evenly across them: each miner requests
+	// (and is limited to) its share, running as many xmrig threads as
+	// whole cores in it.
+	//
+	CPU resource.Quantity `json:"cpu"`
+
+	// Yield scales the miners down whenever other workloads need the CPU.
+	//
+	Yield *MoneroMiningYield `json:"yield,omitempty"`
+}
+
+type MoneroMiningYield struct {
+	// Threshold is the percentage of the allocatable CPU of the cluster
+	// that can be in use (by miners and everything else) before miners
+	// start being scaled down to make room for other workloads.
+	//
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=100
+	//+kubebuilder:default=80
+	Threshold int32 `json:"threshold,omitempty"`
+}
+
+type MoneroMiningPerformance struct {
+	// HugePages makes each miner request huge pages, which xmrig then
+	// allocates the RandomX dataset in.
+	//
+	HugePages *MoneroMiningHugePages `json:"hugePages,omitempty"`
+
+	// MSR applies model-specific register tweaks (by the means of a
+	// privileged init container) to the nodes that miners land on.
+	//
+	MSR *MoneroMiningMSR `json:"msr,omitempty"`
+}
+
+const (
+	HugePageSize2Mi = "2Mi"
+	HugePageSize1Gi = "1Gi"
+)
+
+type MoneroMiningHugePages struct {
+	// Size of the huge pages, `1Gi` pages needing to be supported (and
+	// pre-allocated) by the nodes.
+	//
+	//+kubebuilder:validation:Enum="2Mi";"1Gi"
+	//+kubebuilder:default="2Mi"
+	Size string `json:"size,omitempty"`
+
+	// Amount of memory in huge pages requested by each miner, defaulting
+	// to what a miner with a handful of threads needs (`2560Mi` with 2Mi
+	// pages, `3Gi` with 1Gi ones).
+	//
+	Amount *resource.Quantity `json:"amount,omitempty"`
+}
+
+const (
+	MSRPresetAuto      = "auto"
+	MSRPresetIntel     = "intel"
+	MSRPresetRyzen     = "ryzen"
+	MSRPresetRyzenZen3 = "ryzen-zen3"
+
+	DefaultMSRImage = "index.docker.io/utxobr/msr:v1.0"
+)
+
+type MoneroMiningMSR struct {
+	// Preset is the set of register values to apply, `auto` picking it
+	// based on the node's CPU.
+	//
+	//+kubebuilder:validation:Enum=auto;intel;ryzen;ryzen-zen3
+	//+kubebuilder:default=auto
+	Preset string `json:"preset,omitempty"`
+
+	//+kubebuilder:default="index.docker.io/utxobr/msr:v1.0"
+	Image string `json:"image,omitempty"`
+}
+
+type MoneroMiningSchedule struct {
+	// TimeZone is the IANA name of the time zone (e.g.,
+	// `America/Sao_Paulo`) that the windows are evaluated in.
+	//
+	//+kubebuilder:default=UTC
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows are the periods of time during which mining takes place.
+	// Overlapping windows are merged.
+	//
+	//+kubebuilder:validation:MinItems=1
+	Windows []MoneroMiningWindow `json:"windows"`
+}
+
+type MoneroMiningWindow struct {
+	// Start is a cron expression (`minute hour day-of-month month
+	// day-of-week`) of when the window opens, e.g. `0 22 * * mon-fri`.
+	//
+	Start string `json:"start"`
+
+	// Duration is for how long the window stays open (e.g., `8h`).
+	//
+	Duration metav1.Duration `json:"duration"`
+}
+
+type MoneroMiningPool struct {
+	XmrigPool `json:",inline"`
+
+	// Priority orders the pools, with lower values being preferred.
+	// Pools with the same priority keep the order of the list.
+	//
+	Priority int32 `json:"priority,omitempty"`
+}
+
+type MoneroMiningSoloConfig struct {
+	// NodeSetRef points at the MoneroNodeSet to get block templates
+	// from, which must have its unrestricted RPC interface enabled
+	// without login. Miners only run while it's synchronized.
+	//
+	NodeSetRef MoneroNodeSetReference `json:"nodeSetRef"`
+
+	// Address is the wallet address that block rewards are paid to.
+	//
+	Address string `json:"address,omitempty"`
+
+	// AddressSecretRef points at a key in a Secret (in the same
+	// namespace) holding the payout address, taking precedence over
+	// `address`.
+	//
+	AddressSecretRef *corev1.SecretKeySelector `json:"addressSecretRef,omitempty"`
 }
 
 type XmrigConfig struct {
 	//+kubebuilder:default="index.docker.io/utxobr/xmrig@sha256:a0a231a6fc983885f7fb0ce68fffca027bb2fa032851539901b99ebbfd9140a1"
 	Image string   `json:"image,omitempty"`
 	Args  []string `json:"args,omitempty"`
+
+	// Config is rendered into xmrig's `config.json` (one per miner, so
+	// that `$(id)` can be used in pool credentials), which xmrig is then
+	// started with (`--config`).
+	//
+	Config *XmrigConfigFile `json:"config,omitempty"`
+
+	// AddressSecretRef points at a key in a Secret (in the same
+	// namespace) holding the wallet address to mine to, made available as
+	// `$(address)` and used as the user of pools that don't set one.
+	//
+	AddressSecretRef *corev1.SecretKeySelector `json:"addressSecretRef,omitempty"`
+}
+
+type XmrigConfigFile struct {
+	Pools   []XmrigPool        `json:"pools,omitempty"`
+	CPU     XmrigCPUConfig     `json:"cpu,omitempty"`
+	RandomX XmrigRandomXConfig `json:"randomx,omitempty"`
+	HTTP    XmrigHTTPConfig    `json:"http,omitempty"`
+
+	// DonateLevel is the percentage of time spent mining for xmrig's
+	// developers.
+	//
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=99
+	DonateLevel *int32 `json:"donateLevel,omitempty"`
+}
+
+type XmrigPool struct {
+	// URL is the address of the pool (`host:port`).
+	//
+	URL string `json:"url"`
+
+	// User is usually the wallet address (`$(id)` is replaced by the
+	// index of the miner).
+	//
+	User string `json:"user,omitempty"`
+
+	// Pass is usually used as the worker name (`$(id)` is replaced by
+	// the index of the miner).
+	//
+	Pass string `json:"pass,omitempty"`
+
+	// UserSecretRef points at a key in a Secret (in the same namespace)
+	// holding the user, taking precedence over `user`.
+	//
+	UserSecretRef *corev1.SecretKeySelector `json:"userSecretRef,omitempty"`
+
+	// PassSecretRef points at a key in a Secret (in the same namespace)
+	// holding the password, taking precedence over `pass`.
+	//
+	PassSecretRef *corev1.SecretKeySelector `json:"passSecretRef,omitempty"`
+
+	TLS       bool `json:"tls,omitempty"`
+	Keepalive bool `json:"keepalive,omitempty"`
+	Nicehash  bool `json:"nicehash,omitempty"`
+
+	// Daemon indicates that URL points at monerod's RPC interface rather
+	// than at a pool (i.e., solo mining).
+	//
+	Daemon bool `json:"daemon,omitempty"`
+}
+
+type XmrigCPUConfig struct {
+	//+kubebuilder:default=true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	//+kubebuilder:default=true
+	HugePages *bool `json:"hugePages,omitempty"`
+
+	// MaxThreadsHint limits the number of threads as a percentage of
+	// the available CPUs.
+	//
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=100
+	MaxThreadsHint *int32 `json:"maxThreadsHint,omitempty"`
+
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=5
+	Priority *int32 `json:"priority,omitempty"`
+
+	Yield *bool `json:"yield,omitempty"`
+}
+
+type XmrigRandomXConfig struct {
+	//+kubebuilder:validation:Enum=auto;fast;light
+	//+kubebuilder:default=auto
+	Mode string `json:"mode,omitempty"`
+
+	// InitThreads is the number of threads used for initializing the
+	// dataset (`-1` meaning all of them).
+	//
+	InitThreads *int32 `json:"initThreads,omitempty"`
+
+	OneGBPages bool  `json:"oneGBPages,omitempty"`
+	NUMA       *bool `json:"numa,omitempty"`
+}
+
+// XmrigHTTPConfig configures xmrig's HTTP API, which is always enabled
+// (exposed through the `http` container port) so that the operator can
+// observe the miners.
+//
+type XmrigHTTPConfig struct {
+	AccessToken string `json:"accessToken,omitempty"`
+
+	//+kubebuilder:default=true
+	Restricted *bool `json:"restricted,omitempty"`
 }
 
 type MoneroMiningNodeSetStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Replicas is the number of miners desired.
+	//
+	Replicas uint32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of miners whose pods are ready.
+	//
+	ReadyReplicas uint32 `json:"readyReplicas,omitempty"`
+
+	// Hashrate is the sum of the hashrates reported by the miners.
+	//
+	Hashrate MoneroMiningHashrate `json:"hashrate,omitempty"`
+
+	// Miners holds what each miner reports through xmrig's HTTP API.
+	//
+	Miners []MoneroMinerStatus `json:"miners,omitempty"`
+
+	// Pools reports the health of each pool in `spec.pools`, in order of
+	// preference.
+	//
+	Pools []MoneroMiningPoolStatus `json:"pools,omitempty"`
+
+	// ActivePool is the URL of the pool that the miners are expected to
+	// be on: the most preferred healthy one, as xmrig fails over between
+	// them by itself.
+	//
+	ActivePool string `json:"activePool,omitempty"`
+
+	// Schedule reports where in `spec.schedule` the miners currently are.
+	//
+	Schedule *MoneroMiningScheduleStatus `json:"schedule,omitempty"`
+
+	// Performance reports how effective `spec.performance` has been.
+	//
+	Performance *MoneroMiningPerformanceStatus `json:"performance,omitempty"`
+
+	// Yield reports how many miners got scaled down to make room for
+	// other workloads (see `spec.budget.yield`).
+	//
+	Yield *MoneroMiningYieldStatus `json:"yield,omitempty"`
+
+	// Proxy reports what xmrig-proxy sees (see `spec.xmrigProxy`), summed
+	// across the proxies.
+	//
+	Proxy *MoneroMiningProxyStatus `json:"proxy,omitempty"`
+}
+
+type MoneroMiningProxyStatus struct {
+	// ReadyReplicas is the number of proxies whose pods are ready.
+	//
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// Miners is the number of miners connected to the proxies.
+	//
+	Miners uint64 `json:"miners"`
+
+	// Upstreams is the number of connections to pools, and how many of
+	// those are active and failing.
+	//
+	Upstreams       uint64 `json:"upstreams"`
+	ActiveUpstreams uint64 `json:"activeUpstreams"`
+	ErrorUpstreams  uint64 `json:"errorUpstreams"`
+
+	SharesAccepted uint64 `json:"sharesAccepted"`
+	SharesRejected uint64 `json:"sharesRejected"`
+
+	// Message details why proxies couldn't be observed, if that's the
+	// case.
+	//
+	Message string `json:"message,omitempty"`
+}
+
+type MoneroMiningYieldStatus struct {
+	// CPUPressure is the percentage of the allocatable CPU of the cluster
+	// in use by workloads other than the miners.
+	//
+	CPUPressure int32 `json:"cpuPressure"`
+
+	// YieldedMiners is the number of miners scaled down.
+	//
+	YieldedMiners uint32 `json:"yieldedMiners"`
+
+	// Message details why the pressure couldn't be measured, if that's
+	// the case (with no miners being scaled down).
+	//
+	Message string `json:"message,omitempty"`
+}
+
+type MoneroMiningPerformanceStatus struct {
+	// HugePagesMiners is the number of miners that got all of the huge
+	// pages that they tried to allocate (as reported by xmrig).
+	//
+	HugePagesMiners uint32 `json:"hugePagesMiners"`
+
+	// MSRMiners is the number of miners whose node got the MSR preset
+	// applied.
+	//
+	MSRMiners uint32 `json:"msrMiners"`
+}
+
+type MoneroMiningScheduleStatus struct {
+	// Active tells whether a window is currently open, i.e., whether the
+	// miners are scaled up.
+	//
+	Active bool `json:"active"`
+
+	// NextTransitionTime is when the miners are going to be scaled up (or
+	// down) next, if at all.
+	//
+	NextTransitionTime *metav1.Time `json:"nextTransitionTime,omitempty"`
+}
+
+type MoneroMiningPoolStatus struct {
+	URL      string `json:"url"`
+	Priority int32  `json:"priority,omitempty"`
+	Healthy  bool   `json:"healthy"`
+
+	// Message details why the last probe failed.
+	//
+	Message string `json:"message,omitempty"`
+
+	// Latency is how long the last successful probe (connection and
+	// login) took.
+	//
+	Latency metav1.Duration `json:"latency,omitempty"`
+
+	LastProbeTime      metav1.Time  `json:"lastProbeTime,omitempty"`
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// MoneroMiningHashrate is the hashrate (H/s) averaged over different
+// windows of time.
+//
+type MoneroMiningHashrate struct {
+	TenSeconds     uint64 `json:"tenSeconds,omitempty"`
+	SixtySeconds   uint64 `json:"sixtySeconds,omitempty"`
+	FifteenMinutes uint64 `json:"fifteenMinutes,omitempty"`
+}
+
+type MoneroMinerStatus struct {
+	Index uint32 `json:"index"`
+	Pod   string `json:"pod,omitempty"`
+
+	Hashrate       MoneroMiningHashrate `json:"hashrate,omitempty"`
+	SharesAccepted uint64               `json:"sharesAccepted,omitempty"`
+	SharesRejected uint64               `json:"sharesRejected,omitempty"`
+
+	// Pool is the pool that the miner is currently connected to.
+	//
+	Pool   string          `json:"pool,omitempty"`
+	Uptime metav1.Duration `json:"uptime,omitempty"`
+
+	// HugePages is how many huge pages xmrig managed to allocate out of
+	// those it tried to.
+	//
+	HugePages MoneroMinerHugePages `json:"hugePages,omitempty"`
+
+	// MSR is the MSR preset that got applied to the miner's node, if any.
+	//
+	MSR string `json:"msr,omitempty"`
+}
+
+type MoneroMinerHugePages struct {
+	Allocated uint64 `json:"allocated"`
+	Total     uint64 `json:"total"`
 }
 
 // +kubebuilder:object:root=true
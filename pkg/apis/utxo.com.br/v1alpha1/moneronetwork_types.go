@@ -22,6 +22,31 @@ type MoneroNetworkSpec struct {
 	//+kubebuilder:default=3
 	Replicas uint32                `json:"replicas"`
 	Template MoneroNetworkTemplate `json:"template"`
+	Faults   []MoneroNetworkFault  `json:"faults,omitempty"`
+}
+
+// MoneroNetworkFault describes a network partition to be applied between the
+// members of the network for a given period of time.
+//
+type MoneroNetworkFault struct {
+	Name string `json:"name"`
+
+	// Partition lists the groups of members (the names of the
+	// MoneroNodeSets, e.g. `network-0`) that should be cut from each other.
+	// Members of the same group can still talk to each other.
+	//
+	//+kubebuilder:validation:MinItems=2
+	Partition [][]string `json:"partition"`
+
+	// StartAfter is how long after the fault is first observed by the
+	// operator (as recorded in its status) the partition should take
+	// place.
+	//
+	StartAfter metav1.Duration `json:"startAfter,omitempty"`
+
+	// Duration is how long the partition is kept before being healed.
+	//
+	Duration metav1.Duration `json:"duration"`
 }
 
 type MoneroNetworkTemplate struct {
@@ -30,7 +55,36 @@ type MoneroNetworkTemplate struct {
 }
 
 type MoneroNetworkStatus struct {
-	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Conditions []metav1.Condition         `json:"conditions,omitempty"`
+	Faults     []MoneroNetworkFaultStatus `json:"faults,omitempty"`
+}
+
+const (
+	MoneroNetworkFaultPhasePending = "Pending"
+	MoneroNetworkFaultPhaseActive  = "Active"
+	MoneroNetworkFaultPhaseHealed  = "Healed"
+
+	// MoneroNetworkFaultPhaseInvalid is the phase of a fault that can't
+	// be put in place (e.g., referring to members that aren't part of
+	// the network), with the reason in its message.
+	//
+	MoneroNetworkFaultPhaseInvalid = "Invalid"
+)
+
+type MoneroNetworkFaultStatus struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+
+	// ObservedTime is when the operator first saw the fault, which its
+	// timeline (`startAfter`, `duration`) is relative to.
+	//
+	ObservedTime *metav1.Time `json:"observedTime,omitempty"`
+	StartTime    *metav1.Time `json:"startTime,omitempty"`
+	HealTime     *metav1.Time `json:"healTime,omitempty"`
+
+	// Message tells why the fault is invalid.
+	//
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -9,6 +9,8 @@ import (
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories=monero
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type==\"Ready\")].status`
+// +kubebuilder:printcolumn:name="Synchronized",type=boolean,JSONPath=`.status.synchronized`
+// +kubebuilder:printcolumn:name="Height",type=integer,JSONPath=`.status.height`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 type MoneroNodeSet struct {
@@ -30,8 +32,75 @@ type MoneroNodeSetSpec struct {
 	Service          MoneroNodeSetService `json:"service,omitempty"`
 	StorageClass     string               `json:"storageClass,omitempty"`
 	Tor              MoneroTorConfig      `json:"tor,omitempty"`
+	Peers            []MoneroNodeSetPeer  `json:"peers,omitempty"`
 
 	Monerod MonerodConfig `json:"monerod,omitempty"`
+
+	// TopologySpreadConstraints spreads the nodes evenly across hosts,
+	// zones, or any other topology domain.
+	//
+	TopologySpreadConstraints []MoneroTopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+const (
+	TopologyKeyHostname = "kubernetes.io/hostname"
+	TopologyKeyZone     = "topology.kubernetes.io/zone"
+)
+
+// MoneroTopologySpreadConstraint controls how the pods of a set are spread
+// across the domains of a topology (hosts, zones, etc).
+//
+type MoneroTopologySpreadConstraint struct {
+	// TopologyKey is the node label whose values make up the domains,
+	// e.g. `kubernetes.io/hostname` or `topology.kubernetes.io/zone`.
+	//
+	TopologyKey string `json:"topologyKey"`
+
+	// MaxSkew is the maximum difference in number of pods between any two
+	// domains.
+	//
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable is either `DoNotSchedule` (leaving pods pending)
+	// or `ScheduleAnyway` (only preferring domains that reduce the skew).
+	//
+	//+kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	//+kubebuilder:default=ScheduleAnyway
+	WhenUnsatisfiable string `json:"whenUnsatisfiable,omitempty"`
+}
+
+const (
+	PeerModeExclusive = "exclusive"
+	PeerModePriority  = "priority"
+	PeerModeSeed      = "seed"
+)
+
+// MoneroNodeSetPeer is a peer that the nodes in the set should connect to,
+// either another MoneroNodeSet (resolved to its service) or a literal
+// `host:port` address.
+//
+type MoneroNodeSetPeer struct {
+	NodeSetRef *MoneroNodeSetReference `json:"nodeSetRef,omitempty"`
+	Address    string                  `json:"address,omitempty"`
+
+	// Mode determines how monerod treats the peer: `exclusive`
+	// (--add-exclusive-node), `priority` (--add-priority-node) or `seed`
+	// (--seed-node).
+	//
+	//+kubebuilder:validation:Enum=exclusive;priority;seed
+	//+kubebuilder:default=priority
+	Mode string `json:"mode,omitempty"`
+}
+
+type MoneroNodeSetReference struct {
+	Name string `json:"name"`
+
+	// Namespace of the MoneroNodeSet - defaults to the namespace of the
+	// referencing object.
+	//
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type MoneroNodeSetService struct {
@@ -51,7 +120,13 @@ func (self *MoneroNodeSetSpec) ApplyDefaults() {
 }
 
 type MoneroTorConfig struct {
-	Enabled   bool                        `json:"enabled,omitempty"`
+	Enabled bool `json:"enabled,omitempty"`
+
+	// SecretRef points at a Secret (in the same namespace) holding an
+	// existing hidden service identity to use rather than generating
+	// one: `hs_ed25519_secret_key` as written by tor and, optionally,
+	// `hs_ed25519_public_key` and `hostname`. It's never modified.
+	//
 	SecretRef corev1.LocalObjectReference `json:"secretRef,omitempty"`
 }
 
@@ -59,24 +134,91 @@ type MonerodConfig struct {
 	//+kubebuilder:default=""
 	Image string   `json:"image,omitempty"`
 	Args  []string `json:"args,omitempty"`
+
+	// Network is the Monero network that monerod should be part of,
+	// driving the flags, ports, probes and Tor mappings of the objects
+	// created for the node set.
+	//
+	//+kubebuilder:validation:Enum=mainnet;testnet;stagenet;regtest
+	//+kubebuilder:default=mainnet
+	Network string `json:"network,omitempty"`
+
+	UnrestrictedRPC MonerodUnrestrictedRPCConfig `json:"unrestrictedRPC,omitempty"`
+
+	ZMQ MonerodZMQConfig `json:"zmq,omitempty"`
+}
+
+// MonerodZMQConfig configures the ZMQ publisher of monerod (`--zmq-pub`),
+// exposed within the cluster only through the `<name>-rpc` service.
+//
+// This is turned on automatically when a MoneroP2Pool references the node
+// set.
+//
+type MonerodZMQConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// MonerodUnrestrictedRPCConfig configures the unrestricted (full) RPC
+// interface of monerod, exposed only within the cluster and protected by
+// credentials generated into the `<name>-rpc` Secret.
+//
+type MonerodUnrestrictedRPCConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// DisableLogin drops the requirement of credentials for accessing the
+	// unrestricted RPC interface, needed by clients that can't
+	// authenticate (like xmrig in daemon mode, or p2pool).
+	//
+	// As anything in the cluster that can reach the `<name>-rpc` Service
+	// then gets to administer monerod, this is never turned on
+	// automatically.
+	//
+	DisableLogin bool `json:"disableLogin,omitempty"`
 }
 
 const (
 	DefaultMonerodImage = "index.docker.io/utxobr/monerod@sha256:19ba5793c00375e7115469de9c14fcad928df5867c76ab5de099e83f646e175d"
+
+	NetworkMainnet  = "mainnet"
+	NetworkTestnet  = "testnet"
+	NetworkStagenet = "stagenet"
+	NetworkRegtest  = "regtest"
 )
 
 func (self *MonerodConfig) ApplyDefaults() {
 	if self.Image == "" {
 		self.Image = DefaultMonerodImage
 	}
+
+	if self.Network == "" {
+		self.Network = NetworkMainnet
+	}
 }
 
 type MoneroNodeSetStatus struct {
-	Conditions []metav1.Condition  `json:"conditions,omitempty"`
-	Tor        MoneroNodeStatusTor `json:"tor,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Tor holds the onion address of each replica, as each one is
+	// served by its own hidden service.
+	//
+	// +listType=map
+	// +listMapKey=replica
+	//
+	Tor []MoneroNodeStatusTor `json:"tor,omitempty"`
+
+	// Synchronized indicates whether monerod considers itself in sync
+	// with the rest of the network.
+	//
+	Synchronized bool   `json:"synchronized,omitempty"`
+	Height       uint64 `json:"height,omitempty"`
+	TargetHeight uint64 `json:"targetHeight,omitempty"`
 }
 
 type MoneroNodeStatusTor struct {
+	// Replica is the ordinal of the pod (`<name>-<replica>`) that the
+	// hidden service maps to.
+	//
+	Replica uint32 `json:"replica"`
 	Address string `json:"address,omitempty"`
 }
 
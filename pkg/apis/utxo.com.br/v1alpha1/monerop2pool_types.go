@@ -0,0 +1,114 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=monero
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type==\"Ready\")].status`
+// +kubebuilder:printcolumn:name="Sidechain Height",type=integer,JSONPath=`.status.sidechainHeight`
+// +kubebuilder:printcolumn:name="Shares",type=integer,JSONPath=`.status.sharesFound`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+type MoneroP2Pool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MoneroP2PoolSpec   `json:"spec,omitempty"`
+	Status MoneroP2PoolStatus `json:"status,omitempty"`
+}
+
+func (self *MoneroP2Pool) ApplyDefaults() {
+	self.Spec.ApplyDefaults()
+}
+
+type MoneroP2PoolSpec struct {
+	// NodeSetRef references the MoneroNodeSet that p2pool gets block
+	// templates from. It must live in the same namespace and have its
+	// unrestricted RPC interface enabled without login, getting its ZMQ
+	// publisher enabled for p2pool.
+	//
+	NodeSetRef MoneroNodeSetReference `json:"nodeSetRef"`
+
+	// Address is the wallet address that p2pool pays out to (must be a
+	// primary address).
+	//
+	Address string `json:"address,omitempty"`
+
+	// AddressSecretRef points at a key in a Secret (in the same
+	// namespace) holding the payout address, taking precedence over
+	// `address`.
+	//
+	AddressSecretRef *corev1.SecretKeySelector `json:"addressSecretRef,omitempty"`
+
+	// Mini makes p2pool join the `mini` sidechain, better suited for
+	// smaller hashrates.
+	//
+	Mini bool `json:"mini,omitempty"`
+
+	//+kubebuilder:default=""
+	Image string   `json:"image,omitempty"`
+	Args  []string `json:"args,omitempty"`
+}
+
+// MoneroP2PoolReference identifies a MoneroP2Pool, defaulting to the
+// namespace of the object referencing it.
+//
+type MoneroP2PoolReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+const (
+	DefaultP2PoolImage = "index.docker.io/utxobr/p2pool:v1.9"
+)
+
+func (self *MoneroP2PoolSpec) ApplyDefaults() {
+	if self.Image == "" {
+		self.Image = DefaultP2PoolImage
+	}
+}
+
+type MoneroP2PoolStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// SidechainHeight is the height of the p2pool sidechain.
+	//
+	SidechainHeight uint64 `json:"sidechainHeight,omitempty"`
+
+	// PoolHashrate (H/s) and PoolMiners describe the whole sidechain, not
+	// only the miners behind this instance.
+	//
+	PoolHashrate uint64 `json:"poolHashrate,omitempty"`
+	PoolMiners   uint64 `json:"poolMiners,omitempty"`
+
+	// LocalHashrate is the hashrate (H/s, averaged over 15 minutes) of the
+	// miners connected to this instance.
+	//
+	LocalHashrate uint64 `json:"localHashrate,omitempty"`
+
+	// SharesFound and SharesFailed count the shares submitted to the
+	// sidechain by the miners connected to this instance.
+	//
+	SharesFound  uint64 `json:"sharesFound,omitempty"`
+	SharesFailed uint64 `json:"sharesFailed,omitempty"`
+
+	// Connections is the number of miners connected to the stratum port.
+	//
+	Connections uint64 `json:"connections,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type MoneroP2PoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MoneroP2Pool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MoneroP2Pool{}, &MoneroP2PoolList{})
+}
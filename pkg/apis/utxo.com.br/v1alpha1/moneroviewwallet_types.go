@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=monero
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type==\"Ready\")].status`
+// +kubebuilder:printcolumn:name="Balance",type=integer,JSONPath=`.status.balance`
+// +kubebuilder:printcolumn:name="Unlocked",type=integer,JSONPath=`.status.unlockedBalance`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+type MoneroViewWallet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MoneroViewWalletSpec   `json:"spec,omitempty"`
+	Status MoneroViewWalletStatus `json:"status,omitempty"`
+}
+
+type MoneroViewWalletSpec struct {
+	// NodeSetRef references the MoneroNodeSet that the wallet should be
+	// synchronized against.
+	//
+	NodeSetRef MoneroNodeSetReference `json:"nodeSetRef"`
+
+	// SecretRef references a Secret holding the `address` and `viewKey`
+	// of the wallet to be tracked.
+	//
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+
+	// RestoreHeight is the height from which the wallet should start
+	// scanning the blockchain for incoming transfers.
+	//
+	RestoreHeight uint64 `json:"restoreHeight,omitempty"`
+}
+
+const (
+	ViewWalletAddressKey = "address"
+	ViewWalletViewKeyKey = "viewKey"
+)
+
+type MoneroViewWalletStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Balance and UnlockedBalance are expressed in atomic units.
+	//
+	// ps.: without key images, a view-only wallet can't tell when outputs
+	// have been spent, thus the balance only accounts for incoming funds.
+	//
+	Balance         uint64 `json:"balance,omitempty"`
+	UnlockedBalance uint64 `json:"unlockedBalance,omitempty"`
+	Height          uint64 `json:"height,omitempty"`
+
+	LastIncomingTransfers []MoneroViewWalletTransfer `json:"lastIncomingTransfers,omitempty"`
+}
+
+type MoneroViewWalletTransfer struct {
+	TxID          string      `json:"txid"`
+	Amount        uint64      `json:"amount"`
+	Height        uint64      `json:"height,omitempty"`
+	Confirmations uint64      `json:"confirmations,omitempty"`
+	Timestamp     metav1.Time `json:"timestamp,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type MoneroViewWalletList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MoneroViewWallet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MoneroViewWallet{}, &MoneroViewWalletList{})
+}
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=monero
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type==\"Ready\")].status`
+// +kubebuilder:printcolumn:name="Height",type=integer,JSONPath=`.status.height`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+type MoneroWalletRPC struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MoneroWalletRPCSpec   `json:"spec,omitempty"`
+	Status MoneroWalletRPCStatus `json:"status,omitempty"`
+}
+
+func (self *MoneroWalletRPC) ApplyDefaults() {
+	self.Spec.ApplyDefaults()
+}
+
+type MoneroWalletRPCSpec struct {
+	// NodeSetRef references the MoneroNodeSet that wallet-rpc should use as
+	// its daemon.
+	//
+	NodeSetRef MoneroNodeSetReference `json:"nodeSetRef"`
+
+	// UseUnrestrictedRPC makes wallet-rpc talk to the unrestricted RPC
+	// interface of the node set (which must have it enabled and be in the
+	// same namespace) using its credentials, trusting the daemon.
+	//
+	UseUnrestrictedRPC bool `json:"useUnrestrictedRPC,omitempty"`
+
+	DiskSize     string `json:"diskSize,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+
+	//+kubebuilder:default=""
+	Image string   `json:"image,omitempty"`
+	Args  []string `json:"args,omitempty"`
+}
+
+const (
+	DefaultWalletRPCImage = "index.docker.io/utxobr/monero-wallet-rpc:v0.17.2.0"
+)
+
+func (self *MoneroWalletRPCSpec) ApplyDefaults() {
+	if self.DiskSize == "" {
+		self.DiskSize = "1Gi"
+	}
+
+	if self.Image == "" {
+		self.Image = DefaultWalletRPCImage
+	}
+}
+
+type MoneroWalletRPCStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Height is the blockchain height as seen by the currently opened
+	// wallet, if any.
+	//
+	Height uint64 `json:"height,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type MoneroWalletRPCList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MoneroWalletRPC `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MoneroWalletRPC{}, &MoneroWalletRPCList{})
+}
@@ -5,10 +5,115 @@
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMinerHugePages) DeepCopyInto(out *MoneroMinerHugePages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMinerHugePages.
+func (in *MoneroMinerHugePages) DeepCopy() *MoneroMinerHugePages {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMinerHugePages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMinerStatus) DeepCopyInto(out *MoneroMinerStatus) {
+	*out = *in
+	out.Hashrate = in.Hashrate
+	out.Uptime = in.Uptime
+	out.HugePages = in.HugePages
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMinerStatus.
+func (in *MoneroMinerStatus) DeepCopy() *MoneroMinerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMinerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningBudget) DeepCopyInto(out *MoneroMiningBudget) {
+	*out = *in
+	out.CPU = in.CPU.DeepCopy()
+	if in.Yield != nil {
+		in, out := &in.Yield, &out.Yield
+		*out = new(MoneroMiningYield)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningBudget.
+func (in *MoneroMiningBudget) DeepCopy() *MoneroMiningBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningHashrate) DeepCopyInto(out *MoneroMiningHashrate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningHashrate.
+func (in *MoneroMiningHashrate) DeepCopy() *MoneroMiningHashrate {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningHashrate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningHugePages) DeepCopyInto(out *MoneroMiningHugePages) {
+	*out = *in
+	if in.Amount != nil {
+		in, out := &in.Amount, &out.Amount
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningHugePages.
+func (in *MoneroMiningHugePages) DeepCopy() *MoneroMiningHugePages {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningHugePages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningMSR) DeepCopyInto(out *MoneroMiningMSR) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningMSR.
+func (in *MoneroMiningMSR) DeepCopy() *MoneroMiningMSR {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningMSR)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MoneroMiningNodeSet) DeepCopyInto(out *MoneroMiningNodeSet) {
 	*out = *in
@@ -18,18 +123,772 @@ func (in *MoneroMiningNodeSet) DeepCopyInto(out *MoneroMiningNodeSet) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningNodeSet.
-func (in *MoneroMiningNodeSet) DeepCopy() *MoneroMiningNodeSet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningNodeSet.
+func (in *MoneroMiningNodeSet) DeepCopy() *MoneroMiningNodeSet {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningNodeSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MoneroMiningNodeSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningNodeSetList) DeepCopyInto(out *MoneroMiningNodeSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MoneroMiningNodeSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningNodeSetList.
+func (in *MoneroMiningNodeSetList) DeepCopy() *MoneroMiningNodeSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningNodeSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MoneroMiningNodeSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningNodeSetSpec) DeepCopyInto(out *MoneroMiningNodeSetSpec) {
+	*out = *in
+	in.Xmrig.DeepCopyInto(&out.Xmrig)
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]MoneroTopologySpreadConstraint, len(*in))
+		copy(*out, *in)
+	}
+	if in.Solo != nil {
+		in, out := &in.Solo, &out.Solo
+		*out = new(MoneroMiningSoloConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.P2PoolRef != nil {
+		in, out := &in.P2PoolRef, &out.P2PoolRef
+		*out = new(MoneroP2PoolReference)
+		**out = **in
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]MoneroMiningPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(MoneroMiningSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Performance != nil {
+		in, out := &in.Performance, &out.Performance
+		*out = new(MoneroMiningPerformance)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(MoneroMiningBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.XmrigProxy != nil {
+		in, out := &in.XmrigProxy, &out.XmrigProxy
+		*out = new(MoneroMiningXmrigProxy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningNodeSetSpec.
+func (in *MoneroMiningNodeSetSpec) DeepCopy() *MoneroMiningNodeSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningNodeSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningNodeSetStatus) DeepCopyInto(out *MoneroMiningNodeSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Hashrate = in.Hashrate
+	if in.Miners != nil {
+		in, out := &in.Miners, &out.Miners
+		*out = make([]MoneroMinerStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]MoneroMiningPoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(MoneroMiningScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Performance != nil {
+		in, out := &in.Performance, &out.Performance
+		*out = new(MoneroMiningPerformanceStatus)
+		**out = **in
+	}
+	if in.Yield != nil {
+		in, out := &in.Yield, &out.Yield
+		*out = new(MoneroMiningYieldStatus)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(MoneroMiningProxyStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningNodeSetStatus.
+func (in *MoneroMiningNodeSetStatus) DeepCopy() *MoneroMiningNodeSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningNodeSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningPerformance) DeepCopyInto(out *MoneroMiningPerformance) {
+	*out = *in
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(MoneroMiningHugePages)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MSR != nil {
+		in, out := &in.MSR, &out.MSR
+		*out = new(MoneroMiningMSR)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningPerformance.
+func (in *MoneroMiningPerformance) DeepCopy() *MoneroMiningPerformance {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningPerformance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningPerformanceStatus) DeepCopyInto(out *MoneroMiningPerformanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningPerformanceStatus.
+func (in *MoneroMiningPerformanceStatus) DeepCopy() *MoneroMiningPerformanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningPerformanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningPool) DeepCopyInto(out *MoneroMiningPool) {
+	*out = *in
+	in.XmrigPool.DeepCopyInto(&out.XmrigPool)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningPool.
+func (in *MoneroMiningPool) DeepCopy() *MoneroMiningPool {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningPoolStatus) DeepCopyInto(out *MoneroMiningPoolStatus) {
+	*out = *in
+	out.Latency = in.Latency
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningPoolStatus.
+func (in *MoneroMiningPoolStatus) DeepCopy() *MoneroMiningPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningProxyStatus) DeepCopyInto(out *MoneroMiningProxyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningProxyStatus.
+func (in *MoneroMiningProxyStatus) DeepCopy() *MoneroMiningProxyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningProxyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningSchedule) DeepCopyInto(out *MoneroMiningSchedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]MoneroMiningWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningSchedule.
+func (in *MoneroMiningSchedule) DeepCopy() *MoneroMiningSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningScheduleStatus) DeepCopyInto(out *MoneroMiningScheduleStatus) {
+	*out = *in
+	if in.NextTransitionTime != nil {
+		in, out := &in.NextTransitionTime, &out.NextTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningScheduleStatus.
+func (in *MoneroMiningScheduleStatus) DeepCopy() *MoneroMiningScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningSoloConfig) DeepCopyInto(out *MoneroMiningSoloConfig) {
+	*out = *in
+	out.NodeSetRef = in.NodeSetRef
+	if in.AddressSecretRef != nil {
+		in, out := &in.AddressSecretRef, &out.AddressSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningSoloConfig.
+func (in *MoneroMiningSoloConfig) DeepCopy() *MoneroMiningSoloConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningSoloConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningWindow) DeepCopyInto(out *MoneroMiningWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningWindow.
+func (in *MoneroMiningWindow) DeepCopy() *MoneroMiningWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningXmrigProxy) DeepCopyInto(out *MoneroMiningXmrigProxy) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningXmrigProxy.
+func (in *MoneroMiningXmrigProxy) DeepCopy() *MoneroMiningXmrigProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningXmrigProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningYield) DeepCopyInto(out *MoneroMiningYield) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningYield.
+func (in *MoneroMiningYield) DeepCopy() *MoneroMiningYield {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningYield)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroMiningYieldStatus) DeepCopyInto(out *MoneroMiningYieldStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningYieldStatus.
+func (in *MoneroMiningYieldStatus) DeepCopy() *MoneroMiningYieldStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroMiningYieldStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNetwork) DeepCopyInto(out *MoneroNetwork) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetwork.
+func (in *MoneroNetwork) DeepCopy() *MoneroNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MoneroNetwork) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNetworkFault) DeepCopyInto(out *MoneroNetworkFault) {
+	*out = *in
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = make([][]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+		}
+	}
+	out.StartAfter = in.StartAfter
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkFault.
+func (in *MoneroNetworkFault) DeepCopy() *MoneroNetworkFault {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNetworkFault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNetworkFaultStatus) DeepCopyInto(out *MoneroNetworkFaultStatus) {
+	*out = *in
+	if in.ObservedTime != nil {
+		in, out := &in.ObservedTime, &out.ObservedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.HealTime != nil {
+		in, out := &in.HealTime, &out.HealTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkFaultStatus.
+func (in *MoneroNetworkFaultStatus) DeepCopy() *MoneroNetworkFaultStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNetworkFaultStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNetworkList) DeepCopyInto(out *MoneroNetworkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MoneroNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkList.
+func (in *MoneroNetworkList) DeepCopy() *MoneroNetworkList {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNetworkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MoneroNetworkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNetworkSpec) DeepCopyInto(out *MoneroNetworkSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Faults != nil {
+		in, out := &in.Faults, &out.Faults
+		*out = make([]MoneroNetworkFault, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkSpec.
+func (in *MoneroNetworkSpec) DeepCopy() *MoneroNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNetworkStatus) DeepCopyInto(out *MoneroNetworkStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Faults != nil {
+		in, out := &in.Faults, &out.Faults
+		*out = make([]MoneroNetworkFaultStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkStatus.
+func (in *MoneroNetworkStatus) DeepCopy() *MoneroNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNetworkTemplate) DeepCopyInto(out *MoneroNetworkTemplate) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkTemplate.
+func (in *MoneroNetworkTemplate) DeepCopy() *MoneroNetworkTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNetworkTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNodeSet) DeepCopyInto(out *MoneroNodeSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSet.
+func (in *MoneroNodeSet) DeepCopy() *MoneroNodeSet {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNodeSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MoneroNodeSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNodeSetList) DeepCopyInto(out *MoneroNodeSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MoneroNodeSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetList.
+func (in *MoneroNodeSetList) DeepCopy() *MoneroNodeSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNodeSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MoneroNodeSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNodeSetPeer) DeepCopyInto(out *MoneroNodeSetPeer) {
+	*out = *in
+	if in.NodeSetRef != nil {
+		in, out := &in.NodeSetRef, &out.NodeSetRef
+		*out = new(MoneroNodeSetReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetPeer.
+func (in *MoneroNodeSetPeer) DeepCopy() *MoneroNodeSetPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNodeSetPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNodeSetReference) DeepCopyInto(out *MoneroNodeSetReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetReference.
+func (in *MoneroNodeSetReference) DeepCopy() *MoneroNodeSetReference {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNodeSetReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNodeSetService) DeepCopyInto(out *MoneroNodeSetService) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetService.
+func (in *MoneroNodeSetService) DeepCopy() *MoneroNodeSetService {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNodeSetService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNodeSetSpec) DeepCopyInto(out *MoneroNodeSetSpec) {
+	*out = *in
+	out.Service = in.Service
+	out.Tor = in.Tor
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]MoneroNodeSetPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Monerod.DeepCopyInto(&out.Monerod)
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]MoneroTopologySpreadConstraint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetSpec.
+func (in *MoneroNodeSetSpec) DeepCopy() *MoneroNodeSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNodeSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNodeSetStatus) DeepCopyInto(out *MoneroNodeSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tor != nil {
+		in, out := &in.Tor, &out.Tor
+		*out = make([]MoneroNodeStatusTor, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetStatus.
+func (in *MoneroNodeSetStatus) DeepCopy() *MoneroNodeSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNodeSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroNodeStatusTor) DeepCopyInto(out *MoneroNodeStatusTor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeStatusTor.
+func (in *MoneroNodeStatusTor) DeepCopy() *MoneroNodeStatusTor {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroNodeStatusTor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroP2Pool) DeepCopyInto(out *MoneroP2Pool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroP2Pool.
+func (in *MoneroP2Pool) DeepCopy() *MoneroP2Pool {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroMiningNodeSet)
+	out := new(MoneroP2Pool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MoneroMiningNodeSet) DeepCopyObject() runtime.Object {
+func (in *MoneroP2Pool) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -37,31 +896,31 @@ func (in *MoneroMiningNodeSet) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroMiningNodeSetList) DeepCopyInto(out *MoneroMiningNodeSetList) {
+func (in *MoneroP2PoolList) DeepCopyInto(out *MoneroP2PoolList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MoneroMiningNodeSet, len(*in))
+		*out = make([]MoneroP2Pool, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningNodeSetList.
-func (in *MoneroMiningNodeSetList) DeepCopy() *MoneroMiningNodeSetList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroP2PoolList.
+func (in *MoneroP2PoolList) DeepCopy() *MoneroP2PoolList {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroMiningNodeSetList)
+	out := new(MoneroP2PoolList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MoneroMiningNodeSetList) DeepCopyObject() runtime.Object {
+func (in *MoneroP2PoolList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -69,64 +928,120 @@ func (in *MoneroMiningNodeSetList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroMiningNodeSetSpec) DeepCopyInto(out *MoneroMiningNodeSetSpec) {
+func (in *MoneroP2PoolReference) DeepCopyInto(out *MoneroP2PoolReference) {
 	*out = *in
-	in.Xmrig.DeepCopyInto(&out.Xmrig)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningNodeSetSpec.
-func (in *MoneroMiningNodeSetSpec) DeepCopy() *MoneroMiningNodeSetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroP2PoolReference.
+func (in *MoneroP2PoolReference) DeepCopy() *MoneroP2PoolReference {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroMiningNodeSetSpec)
+	out := new(MoneroP2PoolReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroMiningNodeSetStatus) DeepCopyInto(out *MoneroMiningNodeSetStatus) {
+func (in *MoneroP2PoolSpec) DeepCopyInto(out *MoneroP2PoolSpec) {
+	*out = *in
+	out.NodeSetRef = in.NodeSetRef
+	if in.AddressSecretRef != nil {
+		in, out := &in.AddressSecretRef, &out.AddressSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroP2PoolSpec.
+func (in *MoneroP2PoolSpec) DeepCopy() *MoneroP2PoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroP2PoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroP2PoolStatus) DeepCopyInto(out *MoneroP2PoolStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroMiningNodeSetStatus.
-func (in *MoneroMiningNodeSetStatus) DeepCopy() *MoneroMiningNodeSetStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroP2PoolStatus.
+func (in *MoneroP2PoolStatus) DeepCopy() *MoneroP2PoolStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroMiningNodeSetStatus)
+	out := new(MoneroP2PoolStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNetwork) DeepCopyInto(out *MoneroNetwork) {
+func (in *MoneroTopologySpreadConstraint) DeepCopyInto(out *MoneroTopologySpreadConstraint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroTopologySpreadConstraint.
+func (in *MoneroTopologySpreadConstraint) DeepCopy() *MoneroTopologySpreadConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroTopologySpreadConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroTorConfig) DeepCopyInto(out *MoneroTorConfig) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroTorConfig.
+func (in *MoneroTorConfig) DeepCopy() *MoneroTorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MoneroTorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MoneroViewWallet) DeepCopyInto(out *MoneroViewWallet) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetwork.
-func (in *MoneroNetwork) DeepCopy() *MoneroNetwork {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroViewWallet.
+func (in *MoneroViewWallet) DeepCopy() *MoneroViewWallet {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNetwork)
+	out := new(MoneroViewWallet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MoneroNetwork) DeepCopyObject() runtime.Object {
+func (in *MoneroViewWallet) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -134,31 +1049,31 @@ func (in *MoneroNetwork) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNetworkList) DeepCopyInto(out *MoneroNetworkList) {
+func (in *MoneroViewWalletList) DeepCopyInto(out *MoneroViewWalletList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MoneroNetwork, len(*in))
+		*out = make([]MoneroViewWallet, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkList.
-func (in *MoneroNetworkList) DeepCopy() *MoneroNetworkList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroViewWalletList.
+func (in *MoneroViewWalletList) DeepCopy() *MoneroViewWalletList {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNetworkList)
+	out := new(MoneroViewWalletList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MoneroNetworkList) DeepCopyObject() runtime.Object {
+func (in *MoneroViewWalletList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -166,62 +1081,69 @@ func (in *MoneroNetworkList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNetworkSpec) DeepCopyInto(out *MoneroNetworkSpec) {
+func (in *MoneroViewWalletSpec) DeepCopyInto(out *MoneroViewWalletSpec) {
 	*out = *in
-	in.Template.DeepCopyInto(&out.Template)
+	out.NodeSetRef = in.NodeSetRef
+	out.SecretRef = in.SecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkSpec.
-func (in *MoneroNetworkSpec) DeepCopy() *MoneroNetworkSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroViewWalletSpec.
+func (in *MoneroViewWalletSpec) DeepCopy() *MoneroViewWalletSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNetworkSpec)
+	out := new(MoneroViewWalletSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNetworkStatus) DeepCopyInto(out *MoneroNetworkStatus) {
+func (in *MoneroViewWalletStatus) DeepCopyInto(out *MoneroViewWalletStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastIncomingTransfers != nil {
+		in, out := &in.LastIncomingTransfers, &out.LastIncomingTransfers
+		*out = make([]MoneroViewWalletTransfer, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkStatus.
-func (in *MoneroNetworkStatus) DeepCopy() *MoneroNetworkStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroViewWalletStatus.
+func (in *MoneroViewWalletStatus) DeepCopy() *MoneroViewWalletStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNetworkStatus)
+	out := new(MoneroViewWalletStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNetworkTemplate) DeepCopyInto(out *MoneroNetworkTemplate) {
+func (in *MoneroViewWalletTransfer) DeepCopyInto(out *MoneroViewWalletTransfer) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNetworkTemplate.
-func (in *MoneroNetworkTemplate) DeepCopy() *MoneroNetworkTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroViewWalletTransfer.
+func (in *MoneroViewWalletTransfer) DeepCopy() *MoneroViewWalletTransfer {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNetworkTemplate)
+	out := new(MoneroViewWalletTransfer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNodeSet) DeepCopyInto(out *MoneroNodeSet) {
+func (in *MoneroWalletRPC) DeepCopyInto(out *MoneroWalletRPC) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -229,18 +1151,18 @@ func (in *MoneroNodeSet) DeepCopyInto(out *MoneroNodeSet) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSet.
-func (in *MoneroNodeSet) DeepCopy() *MoneroNodeSet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroWalletRPC.
+func (in *MoneroWalletRPC) DeepCopy() *MoneroWalletRPC {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNodeSet)
+	out := new(MoneroWalletRPC)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MoneroNodeSet) DeepCopyObject() runtime.Object {
+func (in *MoneroWalletRPC) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -248,31 +1170,31 @@ func (in *MoneroNodeSet) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNodeSetList) DeepCopyInto(out *MoneroNodeSetList) {
+func (in *MoneroWalletRPCList) DeepCopyInto(out *MoneroWalletRPCList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MoneroNodeSet, len(*in))
+		*out = make([]MoneroWalletRPC, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetList.
-func (in *MoneroNodeSetList) DeepCopy() *MoneroNodeSetList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroWalletRPCList.
+func (in *MoneroWalletRPCList) DeepCopy() *MoneroWalletRPCList {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNodeSetList)
+	out := new(MoneroWalletRPCList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MoneroNodeSetList) DeepCopyObject() runtime.Object {
+func (in *MoneroWalletRPCList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -280,108 +1202,136 @@ func (in *MoneroNodeSetList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNodeSetService) DeepCopyInto(out *MoneroNodeSetService) {
+func (in *MoneroWalletRPCSpec) DeepCopyInto(out *MoneroWalletRPCSpec) {
 	*out = *in
+	out.NodeSetRef = in.NodeSetRef
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetService.
-func (in *MoneroNodeSetService) DeepCopy() *MoneroNodeSetService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroWalletRPCSpec.
+func (in *MoneroWalletRPCSpec) DeepCopy() *MoneroWalletRPCSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNodeSetService)
+	out := new(MoneroWalletRPCSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNodeSetSpec) DeepCopyInto(out *MoneroNodeSetSpec) {
+func (in *MoneroWalletRPCStatus) DeepCopyInto(out *MoneroWalletRPCStatus) {
 	*out = *in
-	out.Service = in.Service
-	out.Tor = in.Tor
-	in.Monerod.DeepCopyInto(&out.Monerod)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetSpec.
-func (in *MoneroNodeSetSpec) DeepCopy() *MoneroNodeSetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroWalletRPCStatus.
+func (in *MoneroWalletRPCStatus) DeepCopy() *MoneroWalletRPCStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNodeSetSpec)
+	out := new(MoneroWalletRPCStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNodeSetStatus) DeepCopyInto(out *MoneroNodeSetStatus) {
+func (in *MonerodConfig) DeepCopyInto(out *MonerodConfig) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	out.Tor = in.Tor
+	out.UnrestrictedRPC = in.UnrestrictedRPC
+	out.ZMQ = in.ZMQ
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeSetStatus.
-func (in *MoneroNodeSetStatus) DeepCopy() *MoneroNodeSetStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonerodConfig.
+func (in *MonerodConfig) DeepCopy() *MonerodConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNodeSetStatus)
+	out := new(MonerodConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroNodeStatusTor) DeepCopyInto(out *MoneroNodeStatusTor) {
+func (in *MonerodUnrestrictedRPCConfig) DeepCopyInto(out *MonerodUnrestrictedRPCConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroNodeStatusTor.
-func (in *MoneroNodeStatusTor) DeepCopy() *MoneroNodeStatusTor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonerodUnrestrictedRPCConfig.
+func (in *MonerodUnrestrictedRPCConfig) DeepCopy() *MonerodUnrestrictedRPCConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroNodeStatusTor)
+	out := new(MonerodUnrestrictedRPCConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MoneroTorConfig) DeepCopyInto(out *MoneroTorConfig) {
+func (in *MonerodZMQConfig) DeepCopyInto(out *MonerodZMQConfig) {
 	*out = *in
-	out.SecretRef = in.SecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MoneroTorConfig.
-func (in *MoneroTorConfig) DeepCopy() *MoneroTorConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonerodZMQConfig.
+func (in *MonerodZMQConfig) DeepCopy() *MonerodZMQConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MoneroTorConfig)
+	out := new(MonerodZMQConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonerodConfig) DeepCopyInto(out *MonerodConfig) {
+func (in *XmrigCPUConfig) DeepCopyInto(out *XmrigCPUConfig) {
 	*out = *in
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxThreadsHint != nil {
+		in, out := &in.MaxThreadsHint, &out.MaxThreadsHint
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Yield != nil {
+		in, out := &in.Yield, &out.Yield
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonerodConfig.
-func (in *MonerodConfig) DeepCopy() *MonerodConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XmrigCPUConfig.
+func (in *XmrigCPUConfig) DeepCopy() *XmrigCPUConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MonerodConfig)
+	out := new(XmrigCPUConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -394,6 +1344,16 @@ func (in *XmrigConfig) DeepCopyInto(out *XmrigConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(XmrigConfigFile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddressSecretRef != nil {
+		in, out := &in.AddressSecretRef, &out.AddressSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XmrigConfig.
@@ -405,3 +1365,103 @@ func (in *XmrigConfig) DeepCopy() *XmrigConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XmrigConfigFile) DeepCopyInto(out *XmrigConfigFile) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]XmrigPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.CPU.DeepCopyInto(&out.CPU)
+	in.RandomX.DeepCopyInto(&out.RandomX)
+	in.HTTP.DeepCopyInto(&out.HTTP)
+	if in.DonateLevel != nil {
+		in, out := &in.DonateLevel, &out.DonateLevel
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XmrigConfigFile.
+func (in *XmrigConfigFile) DeepCopy() *XmrigConfigFile {
+	if in == nil {
+		return nil
+	}
+	out := new(XmrigConfigFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XmrigHTTPConfig) DeepCopyInto(out *XmrigHTTPConfig) {
+	*out = *in
+	if in.Restricted != nil {
+		in, out := &in.Restricted, &out.Restricted
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XmrigHTTPConfig.
+func (in *XmrigHTTPConfig) DeepCopy() *XmrigHTTPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(XmrigHTTPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XmrigPool) DeepCopyInto(out *XmrigPool) {
+	*out = *in
+	if in.UserSecretRef != nil {
+		in, out := &in.UserSecretRef, &out.UserSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PassSecretRef != nil {
+		in, out := &in.PassSecretRef, &out.PassSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XmrigPool.
+func (in *XmrigPool) DeepCopy() *XmrigPool {
+	if in == nil {
+		return nil
+	}
+	out := new(XmrigPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XmrigRandomXConfig) DeepCopyInto(out *XmrigRandomXConfig) {
+	*out = *in
+	if in.InitThreads != nil {
+		in, out := &in.InitThreads, &out.InitThreads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NUMA != nil {
+		in, out := &in.NUMA, &out.NUMA
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XmrigRandomXConfig.
+func (in *XmrigRandomXConfig) DeepCopy() *XmrigRandomXConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(XmrigRandomXConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -55,6 +55,7 @@ func NewCollector(client *daemonrpc.Client) *Collector {
 
 func (c *Collector) Run(ctx context.Context) error {
 	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
 
 	if err := c.collect(ctx); err != nil {
 		return fmt.Errorf("collect: %w", err)
@@ -67,13 +68,9 @@ func (c *Collector) Run(ctx context.Context) error {
 				return fmt.Errorf("collect: %w", err)
 			}
 		case <-ctx.Done():
-			if err := ctx.Err(); err != nil {
-				return fmt.Errorf("ctx err: %w", err)
-			}
+			return fmt.Errorf("ctx err: %w", ctx.Err())
 		}
 	}
-
-	return nil
 }
 
 type CollectorFunc func(ctx context.Context) error
@@ -36,6 +36,15 @@ type Exporter struct {
 
 type Option func(e *Exporter)
 
+// WithListenAddress overrides the default address (`:9000`) that the
+// exporter listens on.
+//
+func WithListenAddress(addr string) Option {
+	return func(e *Exporter) {
+		e.listenAddress = addr
+	}
+}
+
 func NewExporter(opts ...Option) *Exporter {
 	e := &Exporter{
 		listenAddress: ":9000",
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	minerLabels = []string{"namespace", "set", "miner"}
+
+	minerHashrate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monero_miner_hashrate",
+		Help: "hashrate (H/s) of a miner over a window of time",
+	}, append(minerLabels, "window"))
+
+	minerSharesAccepted = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monero_miner_shares_accepted",
+		Help: "number of shares accepted by the pool",
+	}, minerLabels)
+
+	minerSharesRejected = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monero_miner_shares_rejected",
+		Help: "number of shares rejected by the pool",
+	}, minerLabels)
+
+	minerUptime = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monero_miner_uptime_seconds",
+		Help: "time since the miner started",
+	}, minerLabels)
+
+	// miners keeps track of the miners being reported for each set so
+	// that all of them can be dropped once the set goes away.
+	//
+	miners   = map[[2]string]map[int]bool{}
+	minersMu sync.Mutex
+)
+
+// HashrateWindows are the windows of time over which xmrig reports
+// hashrates, in the same order.
+//
+var HashrateWindows = []string{"10s", "60s", "15m"}
+
+type MinerStats struct {
+	Hashrate       []float64
+	SharesAccepted uint64
+	SharesRejected uint64
+	UptimeSeconds  int64
+}
+
+// SetMiner records the latest observations of a miner in a mining set.
+//
+func SetMiner(namespace, set string, idx int, stats MinerStats) {
+	minersMu.Lock()
+	defer minersMu.Unlock()
+
+	key := [2]string{namespace, set}
+	if miners[key] == nil {
+		miners[key] = map[int]bool{}
+	}
+	miners[key][idx] = true
+
+	miner := strconv.Itoa(idx)
+
+	for i, hashrate := range stats.Hashrate {
+		if i >= len(HashrateWindows) {
+			break
+		}
+
+		minerHashrate.WithLabelValues(namespace, set, miner, HashrateWindows[i]).Set(hashrate)
+	}
+
+	minerSharesAccepted.WithLabelValues(namespace, set, miner).Set(float64(stats.SharesAccepted))
+	minerSharesRejected.WithLabelValues(namespace, set, miner).Set(float64(stats.SharesRejected))
+	minerUptime.WithLabelValues(namespace, set, miner).Set(float64(stats.UptimeSeconds))
+}
+
+// DeleteMiner stops reporting metrics for a single miner of a set.
+//
+func DeleteMiner(namespace, set string, idx int) {
+	minersMu.Lock()
+	defer minersMu.Unlock()
+
+	deleteMiner(namespace, set, idx)
+}
+
+// DeleteMiningSet stops reporting metrics for every miner of a set.
+//
+func DeleteMiningSet(namespace, set string) {
+	minersMu.Lock()
+	defer minersMu.Unlock()
+
+	for idx := range miners[[2]string{namespace, set}] {
+		deleteMiner(namespace, set, idx)
+	}
+}
+
+func deleteMiner(namespace, set string, idx int) {
+	key := [2]string{namespace, set}
+	miner := strconv.Itoa(idx)
+
+	for _, window := range HashrateWindows {
+		minerHashrate.DeleteLabelValues(namespace, set, miner, window)
+	}
+
+	minerSharesAccepted.DeleteLabelValues(namespace, set, miner)
+	minerSharesRejected.DeleteLabelValues(namespace, set, miner)
+	minerUptime.DeleteLabelValues(namespace, set, miner)
+
+	delete(miners[key], idx)
+	if len(miners[key]) == 0 {
+		delete(miners, key)
+	}
+}
@@ -0,0 +1,293 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cirocosta/monero-operator/pkg/tor"
+)
+
+var (
+	torUpDesc = prometheus.NewDesc(
+		"tor_up",
+		"whether tor's control port could be queried",
+		nil, nil,
+	)
+
+	torNetworkLivenessDesc = prometheus.NewDesc(
+		"tor_network_liveness",
+		"whether tor considers the network reachable",
+		nil, nil,
+	)
+
+	torCircuitEstablishedDesc = prometheus.NewDesc(
+		"tor_circuit_established",
+		"whether tor has ever been able to establish a circuit",
+		nil, nil,
+	)
+
+	torReadBytesDesc = prometheus.NewDesc(
+		"tor_read_bytes_total",
+		"number of bytes read by tor",
+		nil, nil,
+	)
+
+	torWrittenBytesDesc = prometheus.NewDesc(
+		"tor_written_bytes_total",
+		"number of bytes written by tor",
+		nil, nil,
+	)
+
+	torDescriptorUploadsDesc = prometheus.NewDesc(
+		"tor_onion_descriptor_uploads_total",
+		"number of onion service descriptor uploads to hsdirs, by result (uploaded or failed)",
+		[]string{"result"}, nil,
+	)
+
+	torDescriptorLastUploadDesc = prometheus.NewDesc(
+		"tor_onion_descriptor_last_upload_timestamp_seconds",
+		"unix time of the last successful onion service descriptor upload",
+		nil, nil,
+	)
+)
+
+const (
+	torDescriptorUploaded = "UPLOADED"
+	torDescriptorFailed   = "FAILED"
+)
+
+// TorCollector exposes the state of a tor instance as observed through its
+// control port: whether it has a working network and circuits, how much
+// traffic it carries and how the uploads of its onion service descriptors
+// go.
+//
+type TorCollector struct {
+	network  string
+	address  string
+	password string
+	interval time.Duration
+	log      logr.Logger
+
+	mu                sync.Mutex
+	up                bool
+	info              map[string]string
+	descriptorUploads map[string]uint64
+	lastUpload        time.Time
+}
+
+type TorCollectorOption func(c *TorCollector)
+
+// WithControlPassword makes the collector authenticate against the control
+// port with a password, rather than with the cookie file (or no
+// authentication at all).
+//
+func WithControlPassword(password string) TorCollectorOption {
+	return func(c *TorCollector) {
+		c.password = password
+	}
+}
+
+// NewTorCollector instantiates a collector targetting tor's control port
+// at `address` (e.g., `127.0.0.1:9051`).
+//
+func NewTorCollector(network, address string, opts ...TorCollectorOption) *TorCollector {
+	c := &TorCollector{
+		network:  network,
+		address:  address,
+		interval: CollectionInterval,
+		log:      log.Log.WithName("tor-collector"),
+		descriptorUploads: map[string]uint64{
+			torDescriptorUploaded: 0,
+			torDescriptorFailed:   0,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Run keeps a connection to the control port for as long as the context
+// isn't done, reconnecting whenever it drops, periodically querying tor and
+// following the uploads of descriptors.
+//
+func (c *TorCollector) Run(ctx context.Context) error {
+	for {
+		if err := c.watch(ctx); err != nil {
+			c.log.Info("control connection failed", "err", err.Error())
+		}
+
+		c.mu.Lock()
+		c.up = false
+		c.mu.Unlock()
+
+		select {
+		case <-time.After(c.interval):
+		case <-ctx.Done():
+			return fmt.Errorf("ctx err: %w", ctx.Err())
+		}
+	}
+}
+
+func (c *TorCollector) watch(ctx context.Context) error {
+	controller, err := tor.DialController(ctx, c.network, c.address)
+	if err != nil {
+		return fmt.Errorf("dial controller: %w", err)
+	}
+
+	defer controller.Close()
+
+	if err := controller.Authenticate(ctx, c.password); err != nil {
+		return fmt.Errorf("authenticate: %w", err)
+	}
+
+	if err := controller.Subscribe(ctx, "HS_DESC"); err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	if err := c.query(ctx, controller); err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	for {
+		select {
+		case event, ok := <-controller.Events():
+			if !ok {
+				return fmt.Errorf("connection closed")
+			}
+
+			c.observeEvent(event)
+		case <-ticker.C:
+			if err := c.query(ctx, controller); err != nil {
+				return fmt.Errorf("query: %w", err)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *TorCollector) query(ctx context.Context, controller *tor.Controller) error {
+	ctx, cancel := context.WithTimeout(ctx, c.interval)
+	defer cancel()
+
+	info, err := controller.GetInfo(ctx,
+		"network-liveness",
+		"status/circuit-established",
+		"traffic/read",
+		"traffic/written",
+	)
+	if err != nil {
+		return fmt.Errorf("getinfo: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.up = true
+	c.info = info
+
+	return nil
+}
+
+// observeEvent accounts for `HS_DESC` events, which tor sends as it uploads
+// descriptors to each hsdir, e.g.:
+//
+//	650 HS_DESC UPLOADED <address> UNKNOWN <hsdir> ...
+//	650 HS_DESC FAILED <address> UNKNOWN <hsdir> ... REASON=...
+//
+func (c *TorCollector) observeEvent(event tor.Event) {
+	if event.Type != "HS_DESC" {
+		return
+	}
+
+	fields := strings.Fields(event.Reply.Lines[0].Text)
+	if len(fields) < 2 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch action := fields[1]; action {
+	case torDescriptorUploaded:
+		c.descriptorUploads[action]++
+		c.lastUpload = time.Now()
+	case torDescriptorFailed:
+		c.descriptorUploads[action]++
+	}
+}
+
+func (c *TorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- torUpDesc
+	ch <- torNetworkLivenessDesc
+	ch <- torCircuitEstablishedDesc
+	ch <- torReadBytesDesc
+	ch <- torWrittenBytesDesc
+	ch <- torDescriptorUploadsDesc
+	ch <- torDescriptorLastUploadDesc
+}
+
+func (c *TorCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(torUpDesc, prometheus.GaugeValue, boolToFloat(c.up))
+
+	for result, count := range c.descriptorUploads {
+		ch <- prometheus.MustNewConstMetric(torDescriptorUploadsDesc,
+			prometheus.CounterValue, float64(count), strings.ToLower(result),
+		)
+	}
+
+	if !c.lastUpload.IsZero() {
+		ch <- prometheus.MustNewConstMetric(torDescriptorLastUploadDesc,
+			prometheus.GaugeValue, float64(c.lastUpload.Unix()),
+		)
+	}
+
+	if !c.up {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(torNetworkLivenessDesc,
+		prometheus.GaugeValue, boolToFloat(c.info["network-liveness"] == "up"),
+	)
+
+	ch <- prometheus.MustNewConstMetric(torCircuitEstablishedDesc,
+		prometheus.GaugeValue, boolToFloat(c.info["status/circuit-established"] == "1"),
+	)
+
+	for key, desc := range map[string]*prometheus.Desc{
+		"traffic/read":    torReadBytesDesc,
+		"traffic/written": torWrittenBytesDesc,
+	} {
+		value, err := strconv.ParseFloat(c.info[key], 64)
+		if err != nil {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
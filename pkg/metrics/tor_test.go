@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cirocosta/monero-operator/pkg/tor"
+	"github.com/cirocosta/monero-operator/pkg/tor/tortest"
+)
+
+const (
+	torUpMetrics = `
+# HELP tor_up whether tor's control port could be queried
+# TYPE tor_up gauge
+tor_up %d
+`
+
+	torInfoMetrics = `
+# HELP tor_network_liveness whether tor considers the network reachable
+# TYPE tor_network_liveness gauge
+tor_network_liveness 1
+# HELP tor_circuit_established whether tor has ever been able to establish a circuit
+# TYPE tor_circuit_established gauge
+tor_circuit_established 1
+# HELP tor_read_bytes_total number of bytes read by tor
+# TYPE tor_read_bytes_total counter
+tor_read_bytes_total 1024
+# HELP tor_written_bytes_total number of bytes written by tor
+# TYPE tor_written_bytes_total counter
+tor_written_bytes_total 2048
+`
+
+	torUploadsMetrics = `
+# HELP tor_onion_descriptor_uploads_total number of onion service descriptor uploads to hsdirs, by result (uploaded or failed)
+# TYPE tor_onion_descriptor_uploads_total counter
+tor_onion_descriptor_uploads_total{result="failed"} %d
+tor_onion_descriptor_uploads_total{result="uploaded"} %d
+`
+)
+
+func hsDescEvent(text string) tor.Event {
+	return tor.Event{
+		Type: "HS_DESC",
+		Reply: &tor.Reply{
+			Status: tor.StatusEvent,
+			Lines:  []tor.ReplyLine{{Status: tor.StatusEvent, Text: text}},
+		},
+	}
+}
+
+func upMetrics(up int) string {
+	return fmt.Sprintf(strings.TrimPrefix(torUpMetrics, "\n"), up)
+}
+
+func uploadsMetrics(failed, uploaded int) string {
+	return fmt.Sprintf(strings.TrimPrefix(torUploadsMetrics, "\n"), failed, uploaded)
+}
+
+func TestTorCollectorObserveEvent(t *testing.T) {
+	c := NewTorCollector("tcp", "127.0.0.1:0")
+
+	for _, event := range []tor.Event{
+		hsDescEvent("HS_DESC UPLOAD abcd UNKNOWN $AAAA~hsdir1 desc-id HSDIR_INDEX=ffff"),
+		hsDescEvent("HS_DESC UPLOADED abcd UNKNOWN $AAAA~hsdir1"),
+		hsDescEvent("HS_DESC UPLOADED abcd UNKNOWN $BBBB~hsdir2"),
+		hsDescEvent("HS_DESC FAILED abcd UNKNOWN $CCCC~hsdir3 REASON=UPLOAD_REJECTED"),
+		hsDescEvent("HS_DESC"),
+		{
+			Type: "CIRC",
+			Reply: &tor.Reply{
+				Status: tor.StatusEvent,
+				Lines:  []tor.ReplyLine{{Status: tor.StatusEvent, Text: "CIRC 1 FAILED"}},
+			},
+		},
+	} {
+		c.observeEvent(event)
+	}
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(uploadsMetrics(1, 2)),
+		"tor_onion_descriptor_uploads_total",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := testutil.CollectAndCount(c, "tor_onion_descriptor_last_upload_timestamp_seconds"); n != 1 {
+		t.Fatalf("expected a last upload timestamp, got %d", n)
+	}
+}
+
+func TestTorCollectorCollectDown(t *testing.T) {
+	c := NewTorCollector("tcp", "127.0.0.1:0")
+
+	if err := testutil.CollectAndCompare(c,
+		strings.NewReader(upMetrics(0)+uploadsMetrics(0, 0)),
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTorCollectorRun(t *testing.T) {
+	server, err := tortest.NewServer()
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	defer server.Close()
+
+	server.Handle("SETEVENTS", func(string) []string {
+		return []string{"250 OK"}
+	})
+	server.Handle("GETINFO", func(string) []string {
+		return []string{
+			"250-network-liveness=up",
+			"250-status/circuit-established=1",
+			"250-traffic/read=1024",
+			"250-traffic/written=2048",
+			"250 OK",
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := NewTorCollector("tcp", server.Addr())
+	c.interval = 50 * time.Millisecond
+
+	runC := make(chan error, 1)
+	go func() { runC <- c.Run(ctx) }()
+
+	waitFor(t, ctx, "getinfo", func() bool {
+		for _, command := range server.Commands() {
+			if strings.HasPrefix(command, "GETINFO") {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	server.Send(
+		"650 HS_DESC UPLOADED abcd UNKNOWN $AAAA~hsdir1",
+		"650 HS_DESC FAILED abcd UNKNOWN $BBBB~hsdir2 REASON=UPLOAD_REJECTED",
+	)
+
+	expected := upMetrics(1) + strings.TrimPrefix(torInfoMetrics, "\n") + uploadsMetrics(1, 1)
+
+	waitFor(t, ctx, "metrics", func() bool {
+		return testutil.CollectAndCompare(c, strings.NewReader(expected),
+			"tor_up",
+			"tor_network_liveness",
+			"tor_circuit_established",
+			"tor_read_bytes_total",
+			"tor_written_bytes_total",
+			"tor_onion_descriptor_uploads_total",
+		) == nil
+	})
+
+	commands := server.Commands()
+	if commands[0] != "PROTOCOLINFO 1" || commands[2] != "SETEVENTS HS_DESC" {
+		t.Fatalf("unexpected commands %v", commands)
+	}
+
+	// with tor gone, only what's known regardless of the connection is
+	// left.
+	//
+	server.Close()
+
+	waitFor(t, ctx, "down", func() bool {
+		return testutil.CollectAndCount(c, "tor_up", "tor_network_liveness") == 1 &&
+			testutil.CollectAndCompare(c, strings.NewReader(upMetrics(0)), "tor_up") == nil
+	})
+
+	cancel()
+	<-runC
+}
+
+func waitFor(t *testing.T, ctx context.Context, what string, cond func() bool) {
+	t.Helper()
+
+	for !cond() {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("waiting for %s: %v", what, ctx.Err())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	viewWalletLabels = []string{"namespace", "name"}
+
+	viewWalletBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monero_view_wallet_balance",
+		Help: "balance of a view-only wallet in atomic units",
+	}, viewWalletLabels)
+
+	viewWalletUnlockedBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monero_view_wallet_unlocked_balance",
+		Help: "unlocked balance of a view-only wallet in atomic units",
+	}, viewWalletLabels)
+
+	viewWalletHeight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "monero_view_wallet_height",
+		Help: "blockchain height as seen by a view-only wallet",
+	}, viewWalletLabels)
+)
+
+// SetViewWallet records the latest observations of a view-only wallet
+// tracked by the operator.
+//
+func SetViewWallet(namespace, name string, balance, unlockedBalance, height uint64) {
+	viewWalletBalance.WithLabelValues(namespace, name).Set(float64(balance))
+	viewWalletUnlockedBalance.WithLabelValues(namespace, name).Set(float64(unlockedBalance))
+	viewWalletHeight.WithLabelValues(namespace, name).Set(float64(height))
+}
+
+// DeleteViewWallet stops reporting metrics for a view-only wallet that is
+// not tracked anymore.
+//
+func DeleteViewWallet(namespace, name string) {
+	viewWalletBalance.DeleteLabelValues(namespace, name)
+	viewWalletUnlockedBalance.DeleteLabelValues(namespace, name)
+	viewWalletHeight.DeleteLabelValues(namespace, name)
+}
@@ -0,0 +1,104 @@
+package p2pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Client reads the statistics that p2pool writes to its data API directory
+// (`--data-api`, with `--local-api`), served over HTTP.
+//
+type Client struct {
+	address    string
+	httpClient *http.Client
+}
+
+// NewClient instantiates a client targetting the HTTP server that exposes
+// p2pool's data API directory (e.g., `http://p2pool.default:3380`).
+//
+func NewClient(address string) *Client {
+	return &Client{
+		address: address,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+type PoolStatistics struct {
+	HashRate            uint64 `json:"hashRate"`
+	Miners              uint64 `json:"miners"`
+	TotalHashes         uint64 `json:"totalHashes"`
+	LastBlockFoundTime  int64  `json:"lastBlockFoundTime"`
+	LastBlockFound      uint64 `json:"lastBlockFound"`
+	TotalBlocksFound    uint64 `json:"totalBlocksFound"`
+	PPLNSWindowSize     uint64 `json:"pplnsWindowSize"`
+	SidechainDifficulty uint64 `json:"sidechainDifficulty"`
+	SidechainHeight     uint64 `json:"sidechainHeight"`
+}
+
+type PoolStats struct {
+	PoolList       []string       `json:"pool_list"`
+	PoolStatistics PoolStatistics `json:"pool_statistics"`
+}
+
+// PoolStats retrieves statistics about the whole sidechain (`pool/stats`).
+//
+func (c *Client) PoolStats(ctx context.Context) (*PoolStats, error) {
+	resp := &PoolStats{}
+	if err := c.get(ctx, "/pool/stats", resp); err != nil {
+		return nil, fmt.Errorf("get pool stats: %w", err)
+	}
+
+	return resp, nil
+}
+
+type LocalStratum struct {
+	Hashrate15m         uint64 `json:"hashrate_15m"`
+	Hashrate1h          uint64 `json:"hashrate_1h"`
+	Hashrate24h         uint64 `json:"hashrate_24h"`
+	TotalHashes         uint64 `json:"total_hashes"`
+	SharesFound         uint64 `json:"shares_found"`
+	SharesFailed        uint64 `json:"shares_failed"`
+	Connections         uint64 `json:"connections"`
+	IncomingConnections uint64 `json:"incoming_connections"`
+}
+
+// LocalStratum retrieves statistics about the miners connected to this
+// p2pool instance (`local/stratum`).
+//
+func (c *Client) LocalStratum(ctx context.Context) (*LocalStratum, error) {
+	resp := &LocalStratum{}
+	if err := c.get(ctx, "/local/stratum", resp); err != nil {
+		return nil, fmt.Errorf("get local stratum: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.address+path, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	return nil
+}
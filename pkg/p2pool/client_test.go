@@ -0,0 +1,110 @@
+package p2pool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestServer serves `testdata` the way the data API directory gets
+// served next to p2pool.
+//
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	return ctx
+}
+
+func TestPoolStats(t *testing.T) {
+	client := NewClient(newTestServer(t).URL)
+
+	stats, err := client.PoolStats(testContext(t))
+	if err != nil {
+		t.Fatalf("pool stats: %v", err)
+	}
+
+	expected := &PoolStats{
+		PoolList: []string{"pplns"},
+		PoolStatistics: PoolStatistics{
+			HashRate:            10839488,
+			Miners:              3214,
+			TotalHashes:         5730470219537493,
+			LastBlockFoundTime:  1665436432,
+			LastBlockFound:      2731702,
+			TotalBlocksFound:    1868,
+			PPLNSWindowSize:     2160,
+			SidechainDifficulty: 108394880,
+			SidechainHeight:     3573091,
+		},
+	}
+
+	if !reflect.DeepEqual(stats, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestLocalStratum(t *testing.T) {
+	client := NewClient(newTestServer(t).URL)
+
+	stratum, err := client.LocalStratum(testContext(t))
+	if err != nil {
+		t.Fatalf("local stratum: %v", err)
+	}
+
+	expected := &LocalStratum{
+		Hashrate15m:         4821,
+		Hashrate1h:          4790,
+		Hashrate24h:         4802,
+		TotalHashes:         413563381,
+		SharesFound:         3,
+		SharesFailed:        0,
+		Connections:         2,
+		IncomingConnections: 2,
+	}
+
+	if !reflect.DeepEqual(stratum, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, stratum)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "not written yet",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+		},
+		{
+			name: "truncated",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`{"pool_list":["pplns"],"pool_stat`))
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			if _, err := NewClient(server.URL).PoolStats(testContext(t)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -7,20 +7,102 @@ const (
 	RestrictedPortName          = "restricted"
 	RestrictedPortNumber uint16 = 18089
 
+	RPCPortName          = "rpc"
+	RPCPortNumber uint16 = 18081
+
+	WalletRPCPortName          = "wallet-rpc"
+	WalletRPCPortNumber uint16 = 18088
+
 	TorProxyPortName          = "tor-proxy"
 	TorProxyPortNumber uint16 = 9050
 
+	TorControlPortNumber uint16 = 9051
+
+	TorMetricsPortName          = "tor-metrics"
+	TorMetricsPortNumber uint16 = 9052
+
 	TorP2PPortName          = "tor-p2p"
 	TorP2PPortNumber uint16 = 18083
 
+	ZMQPubPortName          = "zmq-pub"
+	ZMQPubPortNumber uint16 = 18084
+
+	XmrigHTTPPortName          = "http"
+	XmrigHTTPPortNumber uint16 = 8080
+
+	P2PoolStratumPortName          = "stratum"
+	P2PoolStratumPortNumber uint16 = 3333
+
+	P2PoolP2PPortName              = "p2pool-p2p"
+	P2PoolP2PPortNumber     uint16 = 37889
+	P2PoolMiniP2PPortNumber uint16 = 37888
+
+	XmrigProxyStratumPortName          = "stratum"
+	XmrigProxyStratumPortNumber uint16 = 3333
+
+	P2PoolAPIPortName          = "api"
+	P2PoolAPIPortNumber uint16 = 3380
+
+	TornetesContainerName = "tornetes"
+
+	// TornetesContainerImage is built from images/tornetes (`make
+	// images`). It's referenced by tag until that build is published and
+	// its digest pinned here: the image previously pinned
+	// (sha256:3d103a73...) predates the `--metrics-address` and
+	// `--control-address` flags.
+	//
+	TornetesContainerImage = "index.docker.io/utxobr/tornetes:v0.2.0"
+
 	MonerodContainerName      = "monerod"
 	MonerodContainerImage     = "index.docker.io/utxobr/monerod@sha256:19ba5793c00375e7115469de9c14fcad928df5867c76ab5de099e83f646e175d"
 	MonerodContainerProbePath = "/get_info"
 	MonerodContainerProbePort = RestrictedPortName
 
+	MonerodRPCCredentialsEnvPrefix = "MONEROD_RPC"
+
 	MonerodDataVolumeName      = "data"
 	MonerodDataVolumeMountPath = "/data"
 
 	MonerodConfigVolumeName      = "monerod-conf"
 	MonerodConfigVolumeMountPath = "/monerod-conf"
+
+	MonerodPodNameEnvName = "POD_NAME"
+
+	WalletRPCContainerName = "wallet-rpc"
+
+	WalletDataVolumeName      = "wallets"
+	WalletDataVolumeMountPath = "/wallets"
+
+	XmrigContainerName = "xmrig"
+
+	XmrigConfigVolumeName      = "xmrig-config"
+	XmrigConfigVolumeMountPath = "/etc/xmrig"
+
+	XmrigProxyContainerName  = "xmrig-proxy"
+	XmrigProxyConfigFilename = "config.json"
+
+	XmrigProxyConfigVolumeName      = "xmrig-proxy-config"
+	XmrigProxyConfigVolumeMountPath = "/etc/xmrig-proxy"
+
+	MinerShellContainerName   = "shell"
+	MinerShellImage           = "index.docker.io/library/busybox:1.33"
+	MinerShellVolumeName      = "shell"
+	MinerShellVolumeMountPath = "/opt/shell"
+
+	MSRContainerName          = "msr"
+	MSRModulesVolumeName      = "modules"
+	MSRModulesVolumeMountPath = "/lib/modules"
+
+	ConfigHashAnnotationKey = "utxo.com.br/config-hash"
+	MiningSetLabelKey       = "utxo.com.br/mining-set"
+	NodeSetLabelKey         = "utxo.com.br/node-set"
+	TorReplicaLabelKey      = "utxo.com.br/tor-replica"
+
+	P2PoolContainerName    = "p2pool"
+	P2PoolAPIContainerName = "api"
+	P2PoolAPIImage         = "index.docker.io/library/busybox:1.33"
+	P2PoolAddressEnvName   = "P2POOL_WALLET"
+
+	P2PoolDataVolumeName      = "data"
+	P2PoolDataVolumeMountPath = "/data"
 )
@@ -0,0 +1,129 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	CredentialsUsernameKey = "username"
+	CredentialsPasswordKey = "password"
+
+	DefaultCredentialsUsername = "monero"
+)
+
+func NewCredentialsSecret(name, namespace string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+// FillCredentialsSecret fills a Secret with RPC login credentials, reusing
+// the ones from the Secret already present in the cluster (if any) so that
+// credentials are not rotated on every reconciliation.
+//
+// A nil client (e.g., during a dry-run) always leads to new credentials.
+//
+func FillCredentialsSecret(
+	ctx context.Context,
+	c client.Client,
+	secret *corev1.Secret,
+) error {
+	if c != nil {
+		existing := &corev1.Secret{}
+		err := c.Get(ctx, client.ObjectKey{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		}, existing)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("get %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+
+		if err == nil &&
+			len(existing.Data[CredentialsUsernameKey]) > 0 &&
+			len(existing.Data[CredentialsPasswordKey]) > 0 {
+			secret.Data = existing.Data
+			return nil
+		}
+	}
+
+	password, err := RandomHex(32)
+	if err != nil {
+		return fmt.Errorf("random password: %w", err)
+	}
+
+	secret.Data = map[string][]byte{
+		CredentialsUsernameKey: []byte(DefaultCredentialsUsername),
+		CredentialsPasswordKey: []byte(password),
+	}
+
+	return nil
+}
+
+func RandomHex(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("rand read: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// CredentialsEnv maps the credentials in a Secret to environment variables
+// prefixed with `prefix` (e.g., `RPC_USERNAME` and `RPC_PASSWORD`), so that
+// they can be referenced as `$(RPC_USERNAME)` in a container's command.
+//
+func CredentialsEnv(prefix, secretName string) []corev1.EnvVar {
+	env := []corev1.EnvVar{}
+
+	for _, key := range []string{CredentialsUsernameKey, CredentialsPasswordKey} {
+		env = append(env, corev1.EnvVar{
+			Name: CredentialsEnvName(prefix, key),
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+					Key: key,
+				},
+			},
+		})
+	}
+
+	return env
+}
+
+func CredentialsEnvName(prefix, key string) string {
+	switch key {
+	case CredentialsUsernameKey:
+		return prefix + "_USERNAME"
+	case CredentialsPasswordKey:
+		return prefix + "_PASSWORD"
+	}
+
+	return prefix + "_" + key
+}
+
+// CredentialsLogin is the `username:password` form expected by flags like
+// `--rpc-login` using the variables populated by CredentialsEnv.
+//
+func CredentialsLogin(prefix string) string {
+	return fmt.Sprintf("$(%s):$(%s)",
+		CredentialsEnvName(prefix, CredentialsUsernameKey),
+		CredentialsEnvName(prefix, CredentialsPasswordKey),
+	)
+}
@@ -2,6 +2,9 @@ package reconciler
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -19,22 +22,48 @@ func AppLabel(name string) map[string]string {
 	}
 }
 
-func TorHiddenServiceSecretName(nodeSet *v1alpha1.MoneroNodeSet) string {
-	return nodeSet.Name + "-" + "tor"
+// MonerodPodName is the name of the pod of the replica at index `idx`.
+//
+func MonerodPodName(nodeSet *v1alpha1.MoneroNodeSet, idx int) string {
+	return fmt.Sprintf("%s-%d", nodeSet.Name, idx)
 }
 
-func NewTorHiddenServiceSecret(nodeSet *v1alpha1.MoneroNodeSet) *corev1.Secret {
+// TorLabels are the labels of the objects that make up the hidden service of
+// the replica at index `idx`, used for pruning them once not needed.
+//
+func TorLabels(nodeSet *v1alpha1.MoneroNodeSet, idx int) map[string]string {
+	return map[string]string{
+		NodeSetLabelKey:    nodeSet.Name,
+		TorReplicaLabelKey: strconv.Itoa(idx),
+	}
+}
+
+// TorHiddenServiceSecretName is the name of the Secret holding the identity
+// of the hidden service of the replica at index `idx`: for the first one,
+// the Secret referenced by `tor.secretRef`, if any; otherwise, the one
+// generated by the operator.
+//
+func TorHiddenServiceSecretName(nodeSet *v1alpha1.MoneroNodeSet, idx int) string {
+	if name := nodeSet.Spec.Tor.SecretRef.Name; name != "" && idx == 0 {
+		return name
+	}
+
+	return MonerodPodName(nodeSet, idx) + "-" + "tor"
+}
+
+func NewTorHiddenServiceSecret(nodeSet *v1alpha1.MoneroNodeSet, idx int) *corev1.Secret {
+	labels := TorLabels(nodeSet, idx)
+	labels["utxo.com.br/tor"] = "v3"
+
 	return &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Secret",
 			APIVersion: corev1.SchemeGroupVersion.Identifier(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      TorHiddenServiceSecretName(nodeSet),
+			Name:      TorHiddenServiceSecretName(nodeSet, idx),
 			Namespace: nodeSet.Namespace,
-			Labels: map[string]string{
-				"utxo.com.br/tor": "v3",
-			},
+			Labels:    labels,
 		},
 	}
 }
@@ -43,9 +72,16 @@ func TorProxyConfigMapName(nodeSet *v1alpha1.MoneroNodeSet) string {
 	return nodeSet.Name + "-" + "tor-proxy"
 }
 
+// NewTorProxyConfigMap configures the tor that monerod relays transactions
+// through, with the control port (authenticated through the cookie file)
+// that tornetes gathers metrics from.
+//
 func NewTorProxyConfigMap(nodeSet *v1alpha1.MoneroNodeSet) *corev1.ConfigMap {
-	torrc := `SOCKSPort 9050
-ControlPort 9051`
+	torrc := fmt.Sprintf(`SOCKSPort %d
+ControlPort %d
+CookieAuthentication 1`,
+		TorProxyPortNumber, TorControlPortNumber,
+	)
 
 	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -62,15 +98,30 @@ ControlPort 9051`
 	}
 }
 
-func TorHiddenServiceConfigMapName(nodeSet *v1alpha1.MoneroNodeSet) string {
-	return nodeSet.Name + "-" + "tor-hidden-service"
+func TorHiddenServiceConfigMapName(nodeSet *v1alpha1.MoneroNodeSet, idx int) string {
+	return MonerodPodName(nodeSet, idx) + "-" + "tor-hidden-service"
 }
 
-func NewTorHiddenServiceConfigMap(nodeSet *v1alpha1.MoneroNodeSet) *corev1.ConfigMap {
+// NewTorHiddenServiceConfigMap configures tor to serve the hidden service of
+// the replica at index `idx`, mapping it to the Service that targets that
+// replica's pod alone.
+//
+func NewTorHiddenServiceConfigMap(nodeSet *v1alpha1.MoneroNodeSet, idx int) *corev1.ConfigMap {
+	var (
+		ports   = Ports(nodeSet)
+		service = TorHiddenServiceServiceName(nodeSet, idx)
+	)
+
 	torrc := fmt.Sprintf(`HiddenServiceDir /tor
-HiddenServicePort 18089 %s:18089
-HiddenServicePort 18083 %s:18083
-HiddenServiceVersion 3`, MoneroServiceName(nodeSet), MoneroServiceName(nodeSet))
+HiddenServicePort %d %s:%d
+HiddenServicePort %d %s:%d
+HiddenServiceVersion 3
+ControlPort %d
+CookieAuthentication 1`,
+		ports.Restricted, service, ports.Restricted,
+		ports.TorP2P, service, ports.TorP2P,
+		TorControlPortNumber,
+	)
 
 	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -78,8 +129,9 @@ HiddenServiceVersion 3`, MoneroServiceName(nodeSet), MoneroServiceName(nodeSet))
 			APIVersion: corev1.SchemeGroupVersion.Identifier(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      TorHiddenServiceConfigMapName(nodeSet),
+			Name:      TorHiddenServiceConfigMapName(nodeSet, idx),
 			Namespace: nodeSet.Namespace,
+			Labels:    TorLabels(nodeSet, idx),
 		},
 		Data: map[string]string{
 			"torrc": torrc,
@@ -87,15 +139,23 @@ HiddenServiceVersion 3`, MoneroServiceName(nodeSet), MoneroServiceName(nodeSet))
 	}
 }
 
-func TorHiddenServiceDeploymentName(nodeSet *v1alpha1.MoneroNodeSet) string {
-	return nodeSet.Name + "-tor-proxy"
+func TorHiddenServiceDeploymentName(nodeSet *v1alpha1.MoneroNodeSet, idx int) string {
+	return MonerodPodName(nodeSet, idx) + "-tor-proxy"
 }
 
-func NewTorHiddenServiceDeployment(nodeSet *v1alpha1.MoneroNodeSet) *appsv1.Deployment {
+func NewTorHiddenServiceDeployment(nodeSet *v1alpha1.MoneroNodeSet, idx int) *appsv1.Deployment {
+	name := TorHiddenServiceDeploymentName(nodeSet, idx)
+
+	labels := TorLabels(nodeSet, idx)
+	for k, v := range AppLabel(name) {
+		labels[k] = v
+	}
+
 	o := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      TorHiddenServiceDeploymentName(nodeSet),
+			Name:      name,
 			Namespace: nodeSet.Namespace,
+			Labels:    labels,
 		},
 
 		TypeMeta: metav1.TypeMeta{
@@ -106,16 +166,16 @@ func NewTorHiddenServiceDeployment(nodeSet *v1alpha1.MoneroNodeSet) *appsv1.Depl
 		Spec: appsv1.DeploymentSpec{
 			Replicas: pointer.Int32Ptr(1),
 			Selector: &metav1.LabelSelector{
-				MatchLabels: AppLabel(TorHiddenServiceDeploymentName(nodeSet)),
+				MatchLabels: AppLabel(name),
 			},
 			RevisionHistoryLimit: pointer.Int32Ptr(0),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: AppLabel(TorHiddenServiceDeploymentName(nodeSet)),
+					Labels: AppLabel(name),
 				},
 				Spec: corev1.PodSpec{
 					Volumes: []corev1.Volume{
-						NewTorHiddenServiceVolume(nodeSet),
+						NewTorHiddenServiceVolume(nodeSet, idx),
 					},
 					Containers: []corev1.Container{
 						NewTornetesContainer(nodeSet),
@@ -132,7 +192,7 @@ func TorVolumeName(nodeSet *v1alpha1.MoneroNodeSet) string {
 	return "tor"
 }
 
-func NewTorHiddenServiceVolume(nodeSet *v1alpha1.MoneroNodeSet) corev1.Volume {
+func NewTorHiddenServiceVolume(nodeSet *v1alpha1.MoneroNodeSet, idx int) corev1.Volume {
 	return corev1.Volume{
 		Name: "tor",
 		VolumeSource: corev1.VolumeSource{
@@ -141,14 +201,14 @@ func NewTorHiddenServiceVolume(nodeSet *v1alpha1.MoneroNodeSet) corev1.Volume {
 					{
 						Secret: &corev1.SecretProjection{
 							LocalObjectReference: corev1.LocalObjectReference{
-								Name: TorHiddenServiceSecretName(nodeSet),
+								Name: TorHiddenServiceSecretName(nodeSet, idx),
 							},
 						},
 					},
 					{
 						ConfigMap: &corev1.ConfigMapProjection{
 							LocalObjectReference: corev1.LocalObjectReference{
-								Name: TorHiddenServiceConfigMapName(nodeSet),
+								Name: TorHiddenServiceConfigMapName(nodeSet, idx),
 							},
 						},
 					},
@@ -158,6 +218,87 @@ func NewTorHiddenServiceVolume(nodeSet *v1alpha1.MoneroNodeSet) corev1.Volume {
 	}
 }
 
+func MonerodConfigMapName(nodeSet *v1alpha1.MoneroNodeSet) string {
+	return nodeSet.Name + "-" + "monerod-conf"
+}
+
+// MonerodConfigFilename is the name of the monerod config file of the
+// replica at index `idx`, named after its pod so that it can pick it by
+// itself.
+//
+func MonerodConfigFilename(nodeSet *v1alpha1.MoneroNodeSet, idx int) string {
+	return MonerodPodName(nodeSet, idx) + ".conf"
+}
+
+// NewMonerodConfigMap holds a monerod config file per replica, carrying what
+// differs between them: the onion address each one announces for inbound
+// connections over Tor.
+//
+func NewMonerodConfigMap(nodeSet *v1alpha1.MoneroNodeSet) *corev1.ConfigMap {
+	var (
+		ports = Ports(nodeSet)
+		data  = map[string]string{}
+	)
+
+	for idx := 0; idx < int(nodeSet.Spec.Replicas); idx++ {
+		config := ""
+
+		if address := TorAddress(nodeSet, idx); address != "" {
+			config += fmt.Sprintf("anonymous-inbound=%s:%d,0.0.0.0:%d\n",
+				address, ports.TorP2P, ports.TorP2P,
+			)
+		}
+
+		data[MonerodConfigFilename(nodeSet, idx)] = config
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MonerodConfigMapName(nodeSet),
+			Namespace: nodeSet.Namespace,
+		},
+		Data: data,
+	}
+}
+
+// ConfigMapDigest serializes the contents of a ConfigMap in a stable manner
+// so that it can be hashed (see ConfigHash).
+//
+func ConfigMapDigest(configMap *corev1.ConfigMap) string {
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key + "\n")
+		b.WriteString(configMap.Data[key])
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// TorAddress is the onion address of the replica at index `idx`, as
+// reported in the status.
+//
+func TorAddress(nodeSet *v1alpha1.MoneroNodeSet, idx int) string {
+	for _, status := range nodeSet.Status.Tor {
+		if int(status.Replica) == idx {
+			return status.Address
+		}
+	}
+
+	return ""
+}
+
 func NewTorProxyVolume(nodeSet *v1alpha1.MoneroNodeSet) corev1.Volume {
 	return corev1.Volume{
 		Name: "tor",
@@ -190,37 +331,131 @@ func NewTornetesContainer(nodeSet *v1alpha1.MoneroNodeSet) corev1.Container {
 		"run",
 		"--source=/tor-original/..data",
 		"--destination=/tor",
+		fmt.Sprintf("--metrics-address=:%d", TorMetricsPortNumber),
+		fmt.Sprintf("--control-address=127.0.0.1:%d", TorControlPortNumber),
 	}
 
 	return corev1.Container{
-		Name:         "tornetes",
-		Image:        "index.docker.io/utxobr/tornetes@sha256:3d103a73bca66fb27416b6bd23d3c66cd363157cd9f9d5a159157560ea4c48bf",
-		Command:      command,
+		Name:    TornetesContainerName,
+		Image:   TornetesContainerImage,
+		Command: command,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          TorMetricsPortName,
+				ContainerPort: int32(TorMetricsPortNumber),
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
 		VolumeMounts: volumeMounts,
 	}
 }
 
 func NewMonerodContainer(nodeSet *v1alpha1.MoneroNodeSet) corev1.Container {
-	defaultArgs := []string{
+	ports := Ports(nodeSet)
+
+	defaultArgs := append([]string{
 		"--data-dir=" + MonerodDataVolumeMountPath,
 		"--log-file=/dev/stdout",
 
 		"--non-interactive",
-		"--no-zmq",
 		"--no-igd",
 
 		"--p2p-bind-ip=0.0.0.0",
-		"--p2p-bind-port=18080",
+		fmt.Sprintf("--p2p-bind-port=%d", ports.P2P),
 
 		"--rpc-restricted-bind-ip=0.0.0.0",
-		"--rpc-restricted-bind-port=18089",
+		fmt.Sprintf("--rpc-restricted-bind-port=%d", ports.Restricted),
+	}, NetworkFlags(nodeSet)...)
+
+	env := []corev1.EnvVar{}
+	containerPorts := []corev1.ContainerPort{
+		{
+			Name:          P2PPortName,
+			ContainerPort: int32(ports.P2P),
+			Protocol:      corev1.ProtocolTCP,
+		},
+
+		{
+			Name:          RestrictedPortName,
+			ContainerPort: int32(ports.Restricted),
+			Protocol:      corev1.ProtocolTCP,
+		},
 	}
 
+	if nodeSet.Spec.Monerod.UnrestrictedRPC.Enabled {
+		defaultArgs = append(defaultArgs,
+			"--rpc-bind-ip=0.0.0.0",
+			fmt.Sprintf("--rpc-bind-port=%d", ports.RPC),
+			"--confirm-external-bind",
+		)
+
+		if !nodeSet.Spec.Monerod.UnrestrictedRPC.DisableLogin {
+			defaultArgs = append(defaultArgs,
+				"--rpc-login="+CredentialsLogin(MonerodRPCCredentialsEnvPrefix),
+			)
+
+			env = append(env, CredentialsEnv(
+				MonerodRPCCredentialsEnvPrefix, RPCCredentialsSecretName(nodeSet),
+			)...)
+		}
+
+		containerPorts = append(containerPorts, corev1.ContainerPort{
+			Name:          RPCPortName,
+			ContainerPort: int32(ports.RPC),
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+
+	if nodeSet.Spec.Monerod.ZMQ.Enabled {
+		defaultArgs = append(defaultArgs,
+			fmt.Sprintf("--zmq-pub=tcp://0.0.0.0:%d", ports.ZMQPub),
+		)
+
+		containerPorts = append(containerPorts, corev1.ContainerPort{
+			Name:          ZMQPubPortName,
+			ContainerPort: int32(ports.ZMQPub),
+			Protocol:      corev1.ProtocolTCP,
+		})
+	} else {
+		defaultArgs = append(defaultArgs, "--no-zmq")
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      MonerodDataVolumeName,
+			MountPath: MonerodDataVolumeMountPath,
+		},
+	}
+
+	// each replica picks its own config file (see NewMonerodConfigMap),
+	// listening for inbound connections from its own hidden service.
+	//
 	if nodeSet.Spec.Tor.Enabled {
 		defaultArgs = append(defaultArgs,
 			"--tx-proxy=tor,127.0.0.1:9050",
-			fmt.Sprintf("--anonymous-inbound=%s,%s", nodeSet.Status.Tor.Address+":18083", "127.0.0.1:18083"),
+			"--config-file="+MonerodConfigVolumeMountPath+"/$("+MonerodPodNameEnvName+").conf",
 		)
+
+		env = append(env, corev1.EnvVar{
+			Name: MonerodPodNameEnvName,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		})
+
+		containerPorts = append(containerPorts, corev1.ContainerPort{
+			Name:          TorP2PPortName,
+			ContainerPort: int32(ports.TorP2P),
+			Protocol:      corev1.ProtocolTCP,
+		})
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      MonerodConfigVolumeName,
+			MountPath: MonerodConfigVolumeMountPath,
+			ReadOnly:  true,
+		})
 	}
 
 	command := append([]string{
@@ -231,6 +466,7 @@ func NewMonerodContainer(nodeSet *v1alpha1.MoneroNodeSet) corev1.Container {
 		Name:    MonerodContainerName,
 		Image:   MonerodContainerImage,
 		Command: command,
+		Env:     env,
 		ReadinessProbe: &corev1.Probe{
 			PeriodSeconds:       15,
 			InitialDelaySeconds: 15,
@@ -249,25 +485,8 @@ func NewMonerodContainer(nodeSet *v1alpha1.MoneroNodeSet) corev1.Container {
 			},
 			Requests: corev1.ResourceList{},
 		},
-		Ports: []corev1.ContainerPort{
-			{
-				Name:          P2PPortName,
-				ContainerPort: int32(P2PPortNumber),
-				Protocol:      corev1.ProtocolTCP,
-			},
-
-			{
-				Name:          RestrictedPortName,
-				ContainerPort: int32(RestrictedPortNumber),
-				Protocol:      corev1.ProtocolTCP,
-			},
-		},
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				Name:      MonerodDataVolumeName,
-				MountPath: MonerodDataVolumeMountPath,
-			},
-		},
+		Ports:        containerPorts,
+		VolumeMounts: volumeMounts,
 	}
 
 	return obj
@@ -287,20 +506,69 @@ func NewPodTemplateSpec(nodeSet *v1alpha1.MoneroNodeSet) corev1.PodTemplateSpec
 	}
 
 	if nodeSet.Spec.HardAntiAffinity {
-		o.Spec.Affinity = &corev1.Affinity{
-			PodAntiAffinity: &corev1.PodAntiAffinity{
-				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-					{
-						LabelSelector: &metav1.LabelSelector{
-							MatchLabels: AppLabel(nodeSet.Name),
-						},
+		o.Spec.Affinity = NewHardAntiAffinity(AppLabel(nodeSet.Name))
+	}
+
+	o.Spec.TopologySpreadConstraints = NewTopologySpreadConstraints(
+		nodeSet.Spec.TopologySpreadConstraints,
+		AppLabel(nodeSet.Name),
+	)
+
+	return o
+}
+
+// NewHardAntiAffinity prevents pods matching `labels` from being scheduled
+// onto the same Kubernetes node.
+//
+func NewHardAntiAffinity(labels map[string]string) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: labels,
 					},
+					TopologyKey: v1alpha1.TopologyKeyHostname,
 				},
 			},
+		},
+	}
+}
+
+// NewTopologySpreadConstraints spreads the pods matching `labels` according
+// to the constraints.
+//
+func NewTopologySpreadConstraints(
+	constraints []v1alpha1.MoneroTopologySpreadConstraint,
+	labels map[string]string,
+) []corev1.TopologySpreadConstraint {
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	res := make([]corev1.TopologySpreadConstraint, len(constraints))
+	for idx, constraint := range constraints {
+		maxSkew := constraint.MaxSkew
+		if maxSkew == 0 {
+			maxSkew = 1
+		}
+
+		whenUnsatisfiable := corev1.ScheduleAnyway
+		if constraint.WhenUnsatisfiable != "" {
+			whenUnsatisfiable = corev1.UnsatisfiableConstraintAction(constraint.WhenUnsatisfiable)
+		}
+
+		res[idx] = corev1.TopologySpreadConstraint{
+			MaxSkew:           maxSkew,
+			TopologyKey:       constraint.TopologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
 		}
 	}
 
-	return o
+	return res
 }
 
 func NewVolumeClaimTemplate(nodeSet *v1alpha1.MoneroNodeSet) corev1.PersistentVolumeClaim {
@@ -353,28 +621,50 @@ func NewMoneroStatefulSet(nodeSet *v1alpha1.MoneroNodeSet) *appsv1.StatefulSet {
 		},
 	}
 
-	// tor enabled, let's include the extra volume, as well as secret
+	// tor enabled, let's include the proxy that monerod relays
+	// transactions through, as well as the per-replica config files.
 	//
-	// ps.: this is _true_ only for `replicas==1`
+	// the hidden services themselves run separately (one per replica, see
+	// NewTorHiddenServiceDeployment).
 	//
 	if nodeSet.Spec.Tor.Enabled {
 		obj.Spec.Template.Spec.Volumes = []corev1.Volume{
 			NewTorProxyVolume(nodeSet),
+			{
+				Name: MonerodConfigVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: MonerodConfigMapName(nodeSet),
+						},
+					},
+				},
+			},
 		}
 
 		obj.Spec.Template.Spec.Containers = append(obj.Spec.Template.Spec.Containers,
 			NewTornetesContainer(nodeSet),
 		)
+
+		// monerod only reads its config file on startup, so a change
+		// to it (e.g., a replica's onion address) must roll the pods.
+		//
+		obj.Spec.Template.Annotations = map[string]string{
+			ConfigHashAnnotationKey: ConfigHash(ConfigMapDigest(NewMonerodConfigMap(nodeSet))),
+		}
 	}
 
 	return obj
 }
 
-func TorHiddenServiceServiceName(nodeSet *v1alpha1.MoneroNodeSet) string {
-	return nodeSet.Name + "-tor-hidden-service"
+func TorHiddenServiceServiceName(nodeSet *v1alpha1.MoneroNodeSet, idx int) string {
+	return MonerodPodName(nodeSet, idx) + "-tor-hidden-service"
 }
 
-func NewTorHiddenServiceService(nodeSet *v1alpha1.MoneroNodeSet) *corev1.Service {
+// NewTorHiddenServiceService targets the pod of the replica at index `idx`
+// alone, so that its hidden service maps to it rather than to any replica.
+//
+func NewTorHiddenServiceService(nodeSet *v1alpha1.MoneroNodeSet, idx int) *corev1.Service {
 	obj := &corev1.Service{}
 
 	obj.TypeMeta = metav1.TypeMeta{
@@ -382,21 +672,29 @@ func NewTorHiddenServiceService(nodeSet *v1alpha1.MoneroNodeSet) *corev1.Service
 		APIVersion: corev1.SchemeGroupVersion.Identifier(),
 	}
 
-	l := AppLabel(nodeSet.Name)
-
 	obj.ObjectMeta = metav1.ObjectMeta{
-		Name:      TorHiddenServiceServiceName(nodeSet),
+		Name:      TorHiddenServiceServiceName(nodeSet, idx),
 		Namespace: nodeSet.Namespace,
-		Labels:    l,
+		Labels:    TorLabels(nodeSet, idx),
 	}
 
+	ports := Ports(nodeSet)
+
 	obj.Spec = corev1.ServiceSpec{
-		Selector: l,
+		Selector: map[string]string{
+			appsv1.StatefulSetPodNameLabel: MonerodPodName(nodeSet, idx),
+		},
 		Ports: []corev1.ServicePort{
 			{
 				Name:       TorP2PPortName,
-				Port:       int32(TorP2PPortNumber),
-				TargetPort: intstr.FromInt(int(TorP2PPortNumber)),
+				Port:       int32(ports.TorP2P),
+				TargetPort: intstr.FromInt(int(ports.TorP2P)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+			{
+				Name:       RestrictedPortName,
+				Port:       int32(ports.Restricted),
+				TargetPort: intstr.FromInt(int(ports.Restricted)),
 				Protocol:   corev1.ProtocolTCP,
 			},
 		},
@@ -424,20 +722,22 @@ func NewMoneroService(nodeSet *v1alpha1.MoneroNodeSet) *corev1.Service {
 		Labels:    l,
 	}
 
+	ports := Ports(nodeSet)
+
 	obj.Spec = corev1.ServiceSpec{
 		Selector: l,
 		Ports: []corev1.ServicePort{
 			{
 				Name:       P2PPortName,
-				Port:       int32(P2PPortNumber),
-				TargetPort: intstr.FromInt(int(P2PPortNumber)),
+				Port:       int32(ports.P2P),
+				TargetPort: intstr.FromInt(int(ports.P2P)),
 				Protocol:   corev1.ProtocolTCP,
 			},
 
 			{
 				Name:       RestrictedPortName,
-				Port:       int32(RestrictedPortNumber),
-				TargetPort: intstr.FromInt(int(RestrictedPortNumber)),
+				Port:       int32(ports.Restricted),
+				TargetPort: intstr.FromInt(int(ports.Restricted)),
 				Protocol:   corev1.ProtocolTCP,
 			},
 		},
@@ -446,9 +746,63 @@ func NewMoneroService(nodeSet *v1alpha1.MoneroNodeSet) *corev1.Service {
 	if nodeSet.Spec.Service.Type == "NodePort" {
 		obj.Spec.Type = corev1.ServiceTypeNodePort
 		for idx := range obj.Spec.Ports {
-			obj.Spec.Ports[idx].NodePort = obj.Spec.Ports[idx].Port + 30000 - 18000
+			obj.Spec.Ports[idx].NodePort = ports.NodePort(uint16(obj.Spec.Ports[idx].Port))
 		}
 	}
 
 	return obj
 }
+
+func RPCCredentialsSecretName(nodeSet *v1alpha1.MoneroNodeSet) string {
+	return nodeSet.Name + "-rpc"
+}
+
+func RPCServiceName(nodeSet *v1alpha1.MoneroNodeSet) string {
+	return nodeSet.Name + "-rpc"
+}
+
+// NewRPCService exposes the unrestricted RPC interface (and the ZMQ
+// publisher, if enabled) of monerod within the cluster only - unlike the main
+// service, it's never of type NodePort.
+//
+func NewRPCService(nodeSet *v1alpha1.MoneroNodeSet) *corev1.Service {
+	obj := &corev1.Service{}
+
+	obj.TypeMeta = metav1.TypeMeta{
+		Kind:       "Service",
+		APIVersion: corev1.SchemeGroupVersion.Identifier(),
+	}
+
+	l := AppLabel(nodeSet.Name)
+
+	obj.ObjectMeta = metav1.ObjectMeta{
+		Name:      RPCServiceName(nodeSet),
+		Namespace: nodeSet.Namespace,
+		Labels:    l,
+	}
+
+	ports := Ports(nodeSet)
+
+	obj.Spec = corev1.ServiceSpec{
+		Selector: l,
+		Ports: []corev1.ServicePort{
+			{
+				Name:       RPCPortName,
+				Port:       int32(ports.RPC),
+				TargetPort: intstr.FromInt(int(ports.RPC)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		},
+	}
+
+	if nodeSet.Spec.Monerod.ZMQ.Enabled {
+		obj.Spec.Ports = append(obj.Spec.Ports, corev1.ServicePort{
+			Name:       ZMQPubPortName,
+			Port:       int32(ports.ZMQPub),
+			TargetPort: intstr.FromInt(int(ports.ZMQPub)),
+			Protocol:   corev1.ProtocolTCP,
+		})
+	}
+
+	return obj
+}
@@ -0,0 +1,452 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasttemplate"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+)
+
+const (
+	MinerPodNameEnvName  = "MINER_POD_NAME"
+	MinerNodeNameEnvName = "MINER_NODE_NAME"
+
+	// MinerRefEnvPrefix prefixes the names of the environment variables
+	// that values from Secrets and ConfigMaps referenced in the arguments
+	// get injected through.
+	//
+	MinerRefEnvPrefix = "MINER_REF_"
+
+	// MinerIDScript runs the command it's given with `$(id)` in its
+	// arguments replaced by the ordinal of the pod (the suffix of its
+	// name), which Kubernetes has no way of exposing to the container
+	// directly.
+	//
+	// `$$` keeps Kubernetes from attempting to expand `$(id)` itself.
+	//
+	MinerIDScript = `id=${` + MinerPodNameEnvName + `##*-}
+for arg; do
+	out=
+	while :; do
+		case $arg in
+		*'$$(id)'*) out=$out${arg%%'$$(id)'*}$id; arg=${arg#*'$$(id)'} ;;
+		*) break ;;
+		esac
+	done
+	set -- "$@" "$out$arg"
+	shift
+done
+exec "$@"`
+)
+
+// MinerTemplate expands the `$(...)` placeholders in the arguments and pool
+// credentials of the miners in a set:
+//
+//	$(id)                      index of the miner
+//	$(namespace)               namespace of the mining set
+//	$(set.name)                name of the mining set
+//	$(address)                 wallet address in `xmrig.addressSecretRef`
+//	$(pod.name)                name of the miner's pod (arguments only)
+//	$(node.name)               name of the node the miner runs on (arguments only)
+//	$(secret:<name>/<key>)     key of a Secret
+//	$(configmap:<name>/<key>)  key of a ConfigMap
+//
+// Anything else is rejected rather than being left in place.
+//
+// Values only known at runtime (or that shouldn't end up in the Deployment)
+// are injected as environment variables, which Kubernetes then expands in
+// the container's command. The rendered xmrig configuration, on the other
+// hand, is kept in a Secret, so values from Secrets are resolved right away.
+//
+type MinerTemplate struct {
+	MiningSet *v1alpha1.MoneroMiningNodeSet
+
+	// LookupConfigMap retrieves the value of `key` in the ConfigMap
+	// `name` (in the namespace of the mining set).
+	//
+	LookupConfigMap func(name, key string) (string, error)
+
+	// LookupSecret retrieves the value of `key` in the Secret `name` (in
+	// the namespace of the mining set).
+	//
+	LookupSecret func(name, key string) (string, error)
+}
+
+// NewMinerTemplate creates a template for the miners in the set, looking up
+// referenced ConfigMaps and Secrets only once per reconciliation.
+//
+func (r *MoneroMiningNodeSetReconciler) NewMinerTemplate(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) *MinerTemplate {
+	var (
+		configMaps = map[string]*corev1.ConfigMap{}
+		secrets    = map[string]*corev1.Secret{}
+	)
+
+	return &MinerTemplate{
+		MiningSet: miningSet,
+		LookupConfigMap: func(name, key string) (string, error) {
+			configMap, found := configMaps[name]
+			if !found {
+				configMap = &corev1.ConfigMap{}
+				if err := r.Client.Get(ctx, client.ObjectKey{
+					Name:      name,
+					Namespace: miningSet.Namespace,
+				}, configMap); err != nil {
+					return "", fmt.Errorf("get configmap '%s': %w", name, err)
+				}
+
+				configMaps[name] = configMap
+			}
+
+			value, found := configMap.Data[key]
+			if !found {
+				return "", fmt.Errorf("configmap '%s' has no key '%s'", name, key)
+			}
+
+			return value, nil
+		},
+		LookupSecret: func(name, key string) (string, error) {
+			secret, found := secrets[name]
+			if !found {
+				secret = &corev1.Secret{}
+				if err := r.Client.Get(ctx, client.ObjectKey{
+					Name:      name,
+					Namespace: miningSet.Namespace,
+				}, secret); err != nil {
+					return "", fmt.Errorf("get secret '%s': %w", name, err)
+				}
+
+				secrets[name] = secret
+			}
+
+			value, found := secret.Data[key]
+			if !found {
+				return "", fmt.Errorf("secret '%s' has no key '%s'", name, key)
+			}
+
+			return string(value), nil
+		},
+	}
+}
+
+// Config expands the placeholders in a value that's part of the rendered
+// xmrig configuration, which can only use what's known at reconcile time.
+//
+func (t *MinerTemplate) Config(s string, idx int) (string, error) {
+	return t.execute(s, idx, func(tag string) (string, error) {
+		ref, err := t.SecretRef(tag)
+		if err != nil {
+			return "", err
+		}
+
+		if ref == nil {
+			return "", fmt.Errorf("'$(%s)' can only be used in args", tag)
+		}
+
+		return t.LookupSecret(ref.Name, ref.Key)
+	})
+}
+
+// PoolCredentials resolves the user and password that the miner at `idx`
+// logs into a pool with.
+//
+func (t *MinerTemplate) PoolCredentials(pool v1alpha1.XmrigPool, idx int) (string, string, error) {
+	var (
+		user, pass string
+		err        error
+	)
+
+	switch {
+	case pool.UserSecretRef != nil:
+		user, err = t.LookupSecret(pool.UserSecretRef.Name, pool.UserSecretRef.Key)
+	case pool.User != "":
+		user, err = t.Config(pool.User, idx)
+	case t.MiningSet.Spec.Xmrig.AddressSecretRef != nil:
+		user, err = t.Config("$(address)", idx)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("user: %w", err)
+	}
+
+	if pool.PassSecretRef != nil {
+		pass, err = t.LookupSecret(pool.PassSecretRef.Name, pool.PassSecretRef.Key)
+	} else {
+		pass, err = t.Config(pool.Pass, idx)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("pass: %w", err)
+	}
+
+	return user, pass, nil
+}
+
+// SecretRef determines the key of a Secret that a placeholder refers to, if
+// any.
+//
+func (t *MinerTemplate) SecretRef(tag string) (*corev1.SecretKeySelector, error) {
+	switch {
+	case tag == "address":
+		ref := t.MiningSet.Spec.Xmrig.AddressSecretRef
+		if ref == nil {
+			return nil, fmt.Errorf("'$(address)' requires xmrig.addressSecretRef")
+		}
+
+		return ref, nil
+	case strings.HasPrefix(tag, "secret:"):
+		name, key, err := ParseTemplateRef(strings.TrimPrefix(tag, "secret:"))
+		if err != nil {
+			return nil, err
+		}
+
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// EnvDigest gathers the values of the Secrets that the environment variables
+// of a miner point at, so that changes to them can be reflected in the pod
+// template (as opposed to only being picked up on the next restart).
+//
+func (t *MinerTemplate) EnvDigest(env []corev1.EnvVar) (string, error) {
+	var b strings.Builder
+
+	for _, e := range env {
+		if e.ValueFrom == nil || e.ValueFrom.SecretKeyRef == nil {
+			continue
+		}
+
+		ref := e.ValueFrom.SecretKeyRef
+
+		value, err := t.LookupSecret(ref.Name, ref.Key)
+		if err != nil {
+			return "", fmt.Errorf("env '%s': %w", e.Name, err)
+		}
+
+		b.WriteString(e.Name + "=" + value + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// MinerCommand is the command that all of the miners in a set run.
+//
+type MinerCommand struct {
+	Command []string
+
+	// Env holds the environment variables that the command relies on.
+	//
+	Env []corev1.EnvVar
+
+	// Shell indicates that the command gets run through the shell made
+	// available by MinerShellInitContainer.
+	//
+	Shell bool
+}
+
+// Command expands the placeholders in the command shared by all of the
+// miners.
+//
+// As the index of a miner is only known once its pod exists, the command
+// gets wrapped by MinerIDScript if it makes use of `$(id)`.
+//
+func (t *MinerTemplate) Command(command []string) (*MinerCommand, error) {
+	var (
+		res   = make([]string, len(command))
+		env   = []corev1.EnvVar{}
+		vars  = map[string]string{}
+		refs  = 0
+		useID = false
+	)
+
+	inject := func(key string, source *corev1.EnvVarSource) string {
+		name, found := vars[key]
+		if !found {
+			switch key {
+			case "pod.name":
+				name = MinerPodNameEnvName
+			case "node.name":
+				name = MinerNodeNameEnvName
+			default:
+				name = MinerRefEnvPrefix + strconv.Itoa(refs)
+				refs++
+			}
+
+			vars[key] = name
+			env = append(env, corev1.EnvVar{Name: name, ValueFrom: source})
+		}
+
+		return "$(" + name + ")"
+	}
+
+	for i, arg := range command {
+		var err error
+
+		res[i], err = t.execute(arg, -1, func(tag string) (string, error) {
+			switch tag {
+			case "id":
+				useID = true
+				inject("pod.name", &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				})
+
+				return "$$(id)", nil
+			case "pod.name":
+				return inject(tag, &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				}), nil
+			case "node.name":
+				return inject(tag, &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				}), nil
+			}
+
+			ref, err := t.SecretRef(tag)
+			if err != nil {
+				return "", err
+			}
+
+			if ref == nil {
+				return "", fmt.Errorf("unknown placeholder '$(%s)'", tag)
+			}
+
+			return inject(tag, &corev1.EnvVarSource{SecretKeyRef: ref}), nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("arg '%s': %w", arg, err)
+		}
+	}
+
+	if useID {
+		res = append([]string{
+			MinerShellVolumeMountPath + "/busybox", "sh", "-c", MinerIDScript, "sh",
+		}, res...)
+	}
+
+	return &MinerCommand{
+		Command: res,
+		Env:     env,
+		Shell:   useID,
+	}, nil
+}
+
+// MinerShellInitContainer makes a (static) shell available to the miner
+// through the shell volume, as miner images don't necessarily ship with one.
+//
+func MinerShellInitContainer() corev1.Container {
+	return corev1.Container{
+		Name:    MinerShellContainerName,
+		Image:   MinerShellImage,
+		Command: []string{"cp", "/bin/busybox", MinerShellVolumeMountPath + "/busybox"},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      MinerShellVolumeName,
+				MountPath: MinerShellVolumeMountPath,
+			},
+		},
+	}
+}
+
+// execute expands the placeholders known at reconcile time, deferring to
+// `runtime` for the rest (including `$(id)` when `idx` is negative).
+//
+func (t *MinerTemplate) execute(s string, idx int, runtime func(tag string) (string, error)) (string, error) {
+	return fasttemplate.ExecuteFuncStringWithErr(s, "$(", ")", func(w io.Writer, tag string) (int, error) {
+		var (
+			value string
+			err   error
+		)
+
+		switch {
+		case tag == "id" && idx >= 0:
+			value = strconv.Itoa(idx)
+		case tag == "namespace":
+			value = t.MiningSet.Namespace
+		case tag == "set.name":
+			value = t.MiningSet.Name
+		case strings.HasPrefix(tag, "configmap:"):
+			var name, key string
+
+			name, key, err = ParseTemplateRef(strings.TrimPrefix(tag, "configmap:"))
+			if err == nil {
+				value, err = t.LookupConfigMap(name, key)
+			}
+		case tag == "id", tag == "pod.name", tag == "node.name", tag == "address", strings.HasPrefix(tag, "secret:"):
+			value, err = runtime(tag)
+		default:
+			err = fmt.Errorf("unknown placeholder '$(%s)'", tag)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		return w.Write([]byte(value))
+	})
+}
+
+// ParseTemplateRef splits a `<name>/<key>` reference to a Secret or
+// ConfigMap.
+//
+func ParseTemplateRef(ref string) (string, string, error) {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("malformed reference '%s', expected '<name>/<key>'", ref)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// MiningSetReferencesSecret tells whether the mining set makes use of the
+// Secret `name`, be it through a `*SecretRef` or a placeholder.
+//
+func MiningSetReferencesSecret(miningSet *v1alpha1.MoneroMiningNodeSet, name string) bool {
+	var (
+		placeholder = "$(secret:" + name + "/"
+		xmrig       = miningSet.Spec.Xmrig
+	)
+
+	refers := func(ref *corev1.SecretKeySelector) bool {
+		return ref != nil && ref.Name == name
+	}
+
+	pools := []v1alpha1.XmrigPool{}
+	if xmrig.Config != nil {
+		pools = append(pools, xmrig.Config.Pools...)
+	}
+
+	for _, pool := range miningSet.Spec.Pools {
+		pools = append(pools, pool.XmrigPool)
+	}
+
+	for _, pool := range pools {
+		if refers(pool.UserSecretRef) || refers(pool.PassSecretRef) ||
+			strings.Contains(pool.User, placeholder) || strings.Contains(pool.Pass, placeholder) {
+			return true
+		}
+	}
+
+	for _, arg := range xmrig.Args {
+		if strings.Contains(arg, placeholder) {
+			return true
+		}
+	}
+
+	if solo := miningSet.Spec.Solo; solo != nil && refers(solo.AddressSecretRef) {
+		return true
+	}
+
+	return refers(xmrig.AddressSecretRef)
+}
@@ -0,0 +1,306 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+)
+
+const (
+	// MiningPriorityClassName is the PriorityClass that miners with a
+	// budget run with, making them the first to go when the cluster runs short on
+	// resources.
+	//
+	MiningPriorityClassName = "monero-mining"
+
+	MiningPriority = -100
+
+	DefaultYieldThreshold = 80
+)
+
+var metricsGroupVersion = schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+
+// NewMiningPriorityClass creates the (cluster-wide) low PriorityClass that
+// miners are assigned, which never preempts other pods.
+//
+func NewMiningPriorityClass() *schedulingv1.PriorityClass {
+	never := corev1.PreemptNever
+
+	return &schedulingv1.PriorityClass{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PriorityClass",
+			APIVersion: schedulingv1.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: MiningPriorityClassName,
+		},
+		Value:            MiningPriority,
+		PreemptionPolicy: &never,
+		Description:      "miners managed by monero-operator, soaking up spare CPU",
+	}
+}
+
+// MinerCPU is the share of the CPU budget that each miner gets, if there's a
+// budget at all.
+//
+func MinerCPU(miningSet *v1alpha1.MoneroMiningNodeSet) *resource.Quantity {
+	budget := miningSet.Spec.Budget
+	if budget == nil || miningSet.Spec.Replicas == 0 {
+		return nil
+	}
+
+	millis := budget.CPU.MilliValue() / int64(miningSet.Spec.Replicas)
+	return resource.NewMilliQuantity(millis, resource.DecimalSI)
+}
+
+// MinerThreads is the number of xmrig threads that fit in the share of the
+// CPU budget of each miner (zero meaning that it's up to xmrig).
+//
+func MinerThreads(miningSet *v1alpha1.MoneroMiningNodeSet) int {
+	cpu := MinerCPU(miningSet)
+	if cpu == nil {
+		return 0
+	}
+
+	threads := int(cpu.MilliValue() / 1000)
+	if threads < 1 {
+		threads = 1
+	}
+
+	return threads
+}
+
+// EnsureMiningPriorityClass creates the low PriorityClass that miners with a
+// budget are assigned, if it doesn't exist yet.
+//
+// It's left alone otherwise, so that it's not updated on every
+// reconciliation, and so that a cluster administrator can tweak it.
+//
+func (r *MoneroMiningNodeSetReconciler) EnsureMiningPriorityClass(ctx context.Context) error {
+	existing := &schedulingv1.PriorityClass{}
+	err := r.Client.Get(ctx, client.ObjectKey{Name: MiningPriorityClassName}, existing)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("get priorityclass: %w", err)
+	}
+
+	if err := r.Client.Create(ctx, NewMiningPriorityClass()); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create priorityclass: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyBudget, with a budget, assigns the miners the low PriorityClass and
+// their share of CPU.
+//
+func ApplyBudget(miningSet *v1alpha1.MoneroMiningNodeSet, podSpec *corev1.PodSpec) {
+	if miningSet.Spec.Budget == nil {
+		return
+	}
+
+	podSpec.PriorityClassName = MiningPriorityClassName
+
+	cpu := MinerCPU(miningSet)
+	if cpu == nil {
+		return
+	}
+
+	container := &podSpec.Containers[0]
+
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+
+	container.Resources.Requests[corev1.ResourceCPU] = *cpu
+	container.Resources.Limits[corev1.ResourceCPU] = *cpu
+}
+
+// ResolveYield measures how much of the allocatable CPU of the cluster is in
+// use by workloads other than the miners (through the resource metrics API),
+// scaling the miners down so that, all together, they don't go over the
+// yield threshold.
+//
+// If the usage can't be measured, no miners are scaled down.
+//
+func (r *MoneroMiningNodeSetReconciler) ResolveYield(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) {
+	budget := miningSet.Spec.Budget
+	if budget == nil || budget.Yield == nil {
+		miningSet.Status.Yield = nil
+		return
+	}
+
+	status := &v1alpha1.MoneroMiningYieldStatus{}
+	miningSet.Status.Yield = status
+
+	allocatable, used, mining, err := r.CPUUsage(ctx, miningSet)
+	if err != nil {
+		r.Log.Error(err, "cpu usage", "set", miningSet.Name)
+		status.Message = err.Error()
+		return
+	}
+
+	if allocatable == 0 {
+		status.Message = "no allocatable cpu"
+		return
+	}
+
+	others := used - mining
+	if others < 0 {
+		others = 0
+	}
+
+	threshold := int64(budget.Yield.Threshold)
+	if threshold == 0 {
+		threshold = DefaultYieldThreshold
+	}
+
+	status.CPUPressure = int32(others * 100 / allocatable)
+
+	// with no miners (`replicas: 0`), there's nothing to yield.
+	//
+	cpu := MinerCPU(miningSet)
+	if cpu == nil || miningSet.Spec.Replicas == 0 {
+		return
+	}
+
+	var (
+		perMiner = cpu.MilliValue()
+		spare    = allocatable*threshold/100 - others
+		allowed  = int64(miningSet.Spec.Replicas)
+	)
+
+	if perMiner > 0 && spare/perMiner < allowed {
+		allowed = spare / perMiner
+	}
+
+	if allowed < 0 {
+		allowed = 0
+	}
+
+	status.YieldedMiners = miningSet.Spec.Replicas - uint32(allowed)
+}
+
+// MinersYielded is how many miners are scaled down so that other workloads
+// have the cpu they need.
+//
+func MinersYielded(miningSet *v1alpha1.MoneroMiningNodeSet) uint32 {
+	if miningSet.Status.Yield == nil {
+		return 0
+	}
+
+	return miningSet.Status.Yield.YieldedMiners
+}
+
+// CPUUsage retrieves, in millicores, the allocatable CPU of the schedulable
+// nodes of the cluster, how much of it is in use, and how much of that is
+// used by the miners of the set.
+//
+func (r *MoneroMiningNodeSetReconciler) CPUUsage(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) (int64, int64, int64, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.Client.List(ctx, nodes); err != nil {
+		return 0, 0, 0, fmt.Errorf("list nodes: %w", err)
+	}
+
+	schedulable := map[string]bool{}
+
+	var allocatable int64
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+
+		schedulable[node.Name] = true
+		allocatable += node.Status.Allocatable.Cpu().MilliValue()
+	}
+
+	nodeMetrics := &unstructured.UnstructuredList{}
+	nodeMetrics.SetGroupVersionKind(metricsGroupVersion.WithKind("NodeMetricsList"))
+	if err := r.Client.List(ctx, nodeMetrics); err != nil {
+		return 0, 0, 0, fmt.Errorf("list node metrics: %w", err)
+	}
+
+	var used int64
+	for _, item := range nodeMetrics.Items {
+		if !schedulable[item.GetName()] {
+			continue
+		}
+
+		cpu, err := metricsCPU(item.Object, "usage", "cpu")
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("node '%s': %w", item.GetName(), err)
+		}
+
+		used += cpu
+	}
+
+	podMetrics := &unstructured.UnstructuredList{}
+	podMetrics.SetGroupVersionKind(metricsGroupVersion.WithKind("PodMetricsList"))
+	if err := r.Client.List(ctx, podMetrics,
+		client.InNamespace(miningSet.Namespace),
+		client.MatchingLabels(MinerLabels(miningSet)),
+	); err != nil {
+		return 0, 0, 0, fmt.Errorf("list pod metrics: %w", err)
+	}
+
+	var mining int64
+	for _, item := range podMetrics.Items {
+		containers, _, err := unstructured.NestedSlice(item.Object, "containers")
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("pod '%s': %w", item.GetName(), err)
+		}
+
+		for _, container := range containers {
+			c, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			cpu, err := metricsCPU(c, "usage", "cpu")
+			if err != nil {
+				return 0, 0, 0, fmt.Errorf("pod '%s': %w", item.GetName(), err)
+			}
+
+			mining += cpu
+		}
+	}
+
+	return allocatable, used, mining, nil
+}
+
+func metricsCPU(obj map[string]interface{}, fields ...string) (int64, error) {
+	value, found, err := unstructured.NestedString(obj, fields...)
+	if err != nil || !found {
+		return 0, fmt.Errorf("no cpu usage")
+	}
+
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse '%s': %w", value, err)
+	}
+
+	return q.MilliValue(), nil
+}
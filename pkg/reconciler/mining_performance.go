@@ -0,0 +1,158 @@
+package reconciler
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+)
+
+var (
+	// MinerMemoryRequest is the regular (non huge page) memory requested
+	// by miners that make use of huge pages, as Kubernetes doesn't allow
+	// requesting huge pages alone.
+	//
+	MinerMemoryRequest = resource.MustParse("256Mi")
+
+	DefaultHugePagesAmount = map[string]resource.Quantity{
+		v1alpha1.HugePageSize2Mi: resource.MustParse("2560Mi"),
+		v1alpha1.HugePageSize1Gi: resource.MustParse("3Gi"),
+	}
+)
+
+// HugePagesSize is the size of the huge pages that miners should use, if any.
+//
+func HugePagesSize(miningSet *v1alpha1.MoneroMiningNodeSet) string {
+	perf := miningSet.Spec.Performance
+	if perf == nil || perf.HugePages == nil {
+		return ""
+	}
+
+	if perf.HugePages.Size == "" {
+		return v1alpha1.HugePageSize2Mi
+	}
+
+	return perf.HugePages.Size
+}
+
+// ApplyPerformance tunes the pod spec of the miners according to
+// `spec.performance`: requesting huge pages, and applying the MSR preset to
+// the node before xmrig starts.
+//
+func ApplyPerformance(miningSet *v1alpha1.MoneroMiningNodeSet, podSpec *corev1.PodSpec) {
+	perf := miningSet.Spec.Performance
+	if perf == nil {
+		return
+	}
+
+	container := &podSpec.Containers[0]
+
+	if size := HugePagesSize(miningSet); size != "" {
+		amount := DefaultHugePagesAmount[size]
+		if perf.HugePages.Amount != nil {
+			amount = *perf.HugePages.Amount
+		}
+
+		name := corev1.ResourceName(corev1.ResourceHugePagesPrefix + size)
+
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{}
+		}
+
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{}
+		}
+
+		container.Resources.Requests[name] = amount
+		container.Resources.Limits[name] = amount
+
+		if _, found := container.Resources.Requests[corev1.ResourceMemory]; !found {
+			container.Resources.Requests[corev1.ResourceMemory] = MinerMemoryRequest
+		}
+	}
+
+	if perf.MSR != nil {
+		podSpec.InitContainers = append(podSpec.InitContainers, NewMSRInitContainer(perf.MSR))
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: MSRModulesVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: MSRModulesVolumeMountPath,
+				},
+			},
+		})
+	}
+}
+
+// NewMSRInitContainer creates the privileged container that applies the MSR
+// preset to the node, reporting which one it applied through its termination
+// message.
+//
+func NewMSRInitContainer(msr *v1alpha1.MoneroMiningMSR) corev1.Container {
+	image := msr.Image
+	if image == "" {
+		image = v1alpha1.DefaultMSRImage
+	}
+
+	preset := msr.Preset
+	if preset == "" {
+		preset = v1alpha1.MSRPresetAuto
+	}
+
+	return corev1.Container{
+		Name:  MSRContainerName,
+		Image: image,
+		Args:  []string{preset},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: pointer.BoolPtr(true),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      MSRModulesVolumeName,
+				MountPath: MSRModulesVolumeMountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+// XmrigPerformanceArgs are the flags that match `spec.performance` when
+// xmrig is not configured through a config file.
+//
+func XmrigPerformanceArgs(miningSet *v1alpha1.MoneroMiningNodeSet) []string {
+	args := []string{}
+
+	if HugePagesSize(miningSet) == v1alpha1.HugePageSize1Gi {
+		args = append(args, "--randomx-1gb-pages")
+	}
+
+	if perf := miningSet.Spec.Performance; perf != nil && perf.MSR != nil {
+		args = append(args, "--randomx-wrmsr=-1", "--randomx-no-rdmsr")
+	}
+
+	if threads := MinerThreads(miningSet); threads > 0 {
+		args = append(args, fmt.Sprintf("--threads=%d", threads))
+	}
+
+	return args
+}
+
+// MSRApplied retrieves the MSR preset that the init container of a miner
+// applied, if any.
+//
+func MSRApplied(pod *corev1.Pod) string {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != MSRContainerName {
+			continue
+		}
+
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode == 0 {
+			return terminated.Message
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,248 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+	"github.com/cirocosta/monero-operator/pkg/stratum"
+)
+
+// ResolvePools points the miners at all of the pools in the spec, in order of
+// preference, probing each of them to record their health in the status.
+//
+// Failing over between them is left to xmrig itself, which moves on to the
+// next pool once the one it's using stops working, and back as soon as a
+// more preferred one works again. The pools being rendered into the xmrig
+// configuration regardless of their health keeps miners from being rolled
+// out whenever one of them goes down or comes back up.
+//
+func (r *MoneroMiningNodeSetReconciler) ResolvePools(
+	ctx context.Context,
+	t *MinerTemplate,
+) error {
+	miningSet := t.MiningSet
+
+	if miningSet.Spec.Solo != nil || miningSet.Spec.P2PoolRef != nil {
+		return fmt.Errorf("pools can't be used along with solo or p2poolRef")
+	}
+
+	pools := make([]v1alpha1.MoneroMiningPool, len(miningSet.Spec.Pools))
+	copy(pools, miningSet.Spec.Pools)
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].Priority < pools[j].Priority
+	})
+
+	statuses := r.ProbePools(ctx, t, pools)
+
+	ordered := make([]v1alpha1.XmrigPool, len(pools))
+	for idx, pool := range pools {
+		ordered[idx] = pool.XmrigPool
+	}
+
+	if miningSet.Spec.Xmrig.Config == nil {
+		miningSet.Spec.Xmrig.Config = &v1alpha1.XmrigConfigFile{}
+	}
+
+	miningSet.Spec.Xmrig.Config.Pools = ordered
+	miningSet.Status.Pools = statuses
+	miningSet.Status.ActivePool = ActivePool(pools, statuses)
+
+	return nil
+}
+
+// ActivePool is the URL of the pool that xmrig ends up on: the most preferred
+// healthy one, or (with none healthy) the most preferred one, which xmrig
+// keeps on retrying.
+//
+func ActivePool(
+	pools []v1alpha1.MoneroMiningPool,
+	statuses []v1alpha1.MoneroMiningPoolStatus,
+) string {
+	for idx, status := range statuses {
+		if status.Healthy {
+			return pools[idx].URL
+		}
+	}
+
+	return pools[0].URL
+}
+
+// ProbePools concurrently probes the pools, returning their statuses in the
+// same order.
+//
+// As the credentials can differ from miner to miner (e.g., `$(id)` in the
+// user), each pool is probed with every distinct login that the miners end
+// up using, only being healthy if all of them work.
+//
+func (r *MoneroMiningNodeSetReconciler) ProbePools(
+	ctx context.Context,
+	t *MinerTemplate,
+	pools []v1alpha1.MoneroMiningPool,
+) []v1alpha1.MoneroMiningPoolStatus {
+	var (
+		now      = metav1.Now()
+		statuses = make([]v1alpha1.MoneroMiningPoolStatus, len(pools))
+		wg       sync.WaitGroup
+	)
+
+	for idx, pool := range pools {
+		previous := r.PoolStatus(t.MiningSet, pool.URL)
+
+		statuses[idx] = v1alpha1.MoneroMiningPoolStatus{
+			URL:                pool.URL,
+			Priority:           pool.Priority,
+			LastProbeTime:      now,
+			LastTransitionTime: previous.LastTransitionTime,
+		}
+
+		// credentials are resolved upfront as configmap and secret
+		// lookups aren't meant to happen concurrently.
+		//
+		logins, credentialsErr := PoolLogins(t, pool.XmrigPool)
+
+		wg.Add(1)
+		go func(status *v1alpha1.MoneroMiningPoolStatus, logins []PoolLogin, wasHealthy bool) {
+			defer wg.Done()
+
+			var (
+				latency time.Duration
+				err     error
+			)
+
+			if credentialsErr != nil {
+				err = credentialsErr
+			} else {
+				latency, err = ProbePoolLogins(ctx, logins)
+			}
+
+			if err != nil {
+				status.Message = err.Error()
+			} else {
+				status.Healthy = true
+				status.Latency = metav1.Duration{Duration: latency}
+			}
+
+			if status.LastTransitionTime == nil || status.Healthy != wasHealthy {
+				status.LastTransitionTime = &now
+			}
+		}(&statuses[idx], logins, previous.Healthy)
+	}
+
+	wg.Wait()
+
+	return statuses
+}
+
+// PoolLogin is a pool with the credentials of a particular miner expanded.
+//
+type PoolLogin struct {
+	v1alpha1.XmrigPool
+
+	// Miner is the index of the first miner logging in this way.
+	//
+	Miner int
+}
+
+// PoolLogins resolves the distinct credentials that the miners in the set
+// log into a pool with (at least those of the first miner, even with the set
+// scaled to zero).
+//
+func PoolLogins(t *MinerTemplate, pool v1alpha1.XmrigPool) ([]PoolLogin, error) {
+	var (
+		logins = []PoolLogin{}
+		seen   = map[[2]string]bool{}
+	)
+
+	for idx := 0; idx == 0 || idx < int(t.MiningSet.Spec.Replicas); idx++ {
+		user, pass, err := t.PoolCredentials(pool, idx)
+		if err != nil {
+			return nil, fmt.Errorf("miner %d: %w", idx, err)
+		}
+
+		if seen[[2]string{user, pass}] {
+			continue
+		}
+
+		seen[[2]string{user, pass}] = true
+
+		login := PoolLogin{XmrigPool: pool, Miner: idx}
+		login.User, login.Pass = user, pass
+
+		logins = append(logins, login)
+	}
+
+	return logins, nil
+}
+
+// ProbePoolLogins probes a pool with each of the logins in turn, stopping at
+// the first one that fails, and returning the highest latency otherwise.
+//
+func ProbePoolLogins(ctx context.Context, logins []PoolLogin) (time.Duration, error) {
+	var highest time.Duration
+
+	for _, login := range logins {
+		latency, err := ProbePool(ctx, login.XmrigPool)
+		if err != nil {
+			if len(logins) == 1 {
+				return 0, err
+			}
+
+			return 0, fmt.Errorf("miner %d: %w", login.Miner, err)
+		}
+
+		if latency > highest {
+			highest = latency
+		}
+	}
+
+	return highest, nil
+}
+
+// ProbePool checks whether a pool is able to give miners work: for regular
+// pools, by going through the stratum login (with already expanded
+// credentials); for daemons, by connecting to their RPC port.
+//
+func ProbePool(ctx context.Context, pool v1alpha1.XmrigPool) (time.Duration, error) {
+	if pool.Daemon {
+		start := time.Now()
+
+		ctx, cancel := context.WithTimeout(ctx, stratum.DefaultProbeTimeout)
+		defer cancel()
+
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", pool.URL)
+		if err != nil {
+			return 0, fmt.Errorf("dial: %w", err)
+		}
+
+		conn.Close()
+		return time.Since(start), nil
+	}
+
+	return stratum.Probe(ctx, stratum.ProbeOptions{
+		Address: pool.URL,
+		TLS:     pool.TLS,
+		User:    pool.User,
+		Pass:    pool.Pass,
+	})
+}
+
+func (r *MoneroMiningNodeSetReconciler) PoolStatus(
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+	url string,
+) v1alpha1.MoneroMiningPoolStatus {
+	for _, status := range miningSet.Status.Pools {
+		if status.URL == url {
+			return status
+		}
+	}
+
+	return v1alpha1.MoneroMiningPoolStatus{URL: url}
+}
@@ -0,0 +1,425 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+	"github.com/cirocosta/monero-operator/pkg/xmrig"
+)
+
+// XmrigProxyJSONConfig is the subset of xmrig-proxy's `config.json` that
+// the operator manages.
+//
+type XmrigProxyJSONConfig struct {
+	Background  bool                 `json:"background"`
+	Colors      bool                 `json:"colors"`
+	DonateLevel *int32               `json:"donate-level,omitempty"`
+	Mode        string               `json:"mode"`
+	Workers     bool                 `json:"workers"`
+	Bind        []XmrigProxyJSONBind `json:"bind"`
+	HTTP        XmrigJSONHTTPConfig  `json:"http"`
+	Pools       []XmrigJSONPoolEntry `json:"pools"`
+}
+
+type XmrigProxyJSONBind struct {
+	Host string `json:"host"`
+	Port uint16 `json:"port"`
+	TLS  bool   `json:"tls"`
+}
+
+func XmrigProxyName(miningSet *v1alpha1.MoneroMiningNodeSet) string {
+	return miningSet.Name + "-proxy"
+}
+
+// XmrigProxyStratumAddress is the `host:port` address of the Service that
+// miners connect to rather than to the pools.
+//
+func XmrigProxyStratumAddress(miningSet *v1alpha1.MoneroMiningNodeSet) string {
+	return fmt.Sprintf("%s.%s:%d",
+		XmrigProxyName(miningSet), miningSet.Namespace, XmrigProxyStratumPortNumber,
+	)
+}
+
+// ReconcileXmrigProxy deploys xmrig-proxy with the pools that the miners
+// would otherwise connect to, pointing the miners at it instead.
+//
+// Upstream, the credentials of the pools are those of the first miner (i.e.,
+// `$(id)` being `0`), while each miner still logs in to the proxy with its
+// own so that they can be told apart as workers.
+//
+func (r *MoneroMiningNodeSetReconciler) ReconcileXmrigProxy(
+	ctx context.Context,
+	t *MinerTemplate,
+) error {
+	miningSet := t.MiningSet
+
+	if miningSet.Spec.Solo != nil {
+		return fmt.Errorf("xmrigProxy can't be used along with solo")
+	}
+
+	config := miningSet.Spec.Xmrig.Config
+	if config == nil || len(config.Pools) == 0 {
+		return fmt.Errorf("xmrigProxy requires pools to be set in the xmrig config")
+	}
+
+	secret, err := NewXmrigProxyConfigSecret(t)
+	if err != nil {
+		return fmt.Errorf("new config secret: %w", err)
+	}
+
+	deployment := NewXmrigProxyDeployment(miningSet, ConfigHash(SecretDigest(secret)))
+	service := NewXmrigProxyService(miningSet)
+
+	for _, obj := range []client.Object{secret, deployment, service} {
+		r.SetOwnerRef(miningSet, obj)
+
+		if err := r.Apply(ctx, obj); err != nil {
+			return fmt.Errorf("apply %s '%s': %w",
+				obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err,
+			)
+		}
+	}
+
+	first := config.Pools[0]
+
+	config.Pools = []v1alpha1.XmrigPool{
+		{
+			URL:           XmrigProxyStratumAddress(miningSet),
+			User:          first.User,
+			Pass:          first.Pass,
+			UserSecretRef: first.UserSecretRef,
+			PassSecretRef: first.PassSecretRef,
+			Keepalive:     true,
+			Nicehash:      miningSet.Spec.XmrigProxy.Mode != v1alpha1.XmrigProxyModeSimple,
+		},
+	}
+
+	return nil
+}
+
+// PruneXmrigProxy removes the objects that make up xmrig-proxy, if any, for
+// when it's not wanted anymore.
+//
+// Only objects controlled by the mining set are removed, leaving alone any
+// that just happen to share the name.
+//
+func (r *MoneroMiningNodeSetReconciler) PruneXmrigProxy(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) error {
+	key := client.ObjectKey{
+		Name:      XmrigProxyName(miningSet),
+		Namespace: miningSet.Namespace,
+	}
+
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{},
+		&corev1.Service{},
+		&corev1.Secret{},
+	} {
+		if err := r.Client.Get(ctx, key, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return fmt.Errorf("get '%s': %w", key.Name, err)
+		}
+
+		if !metav1.IsControlledBy(obj, miningSet) {
+			continue
+		}
+
+		if err := r.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete '%s': %w", obj.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// ObserveXmrigProxy gathers, through their HTTP API, what each ready proxy
+// reports about the miners and the upstream connections.
+//
+// Proxies that can't be reached are left out rather than failing the whole
+// reconciliation.
+//
+func (r *MoneroMiningNodeSetReconciler) ObserveXmrigProxy(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) error {
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods,
+		client.InNamespace(miningSet.Namespace),
+		client.MatchingLabels(AppLabel(XmrigProxyName(miningSet))),
+	); err != nil {
+		return fmt.Errorf("list pods: %w", err)
+	}
+
+	opts := []xmrig.ClientOption{}
+	if config := miningSet.Spec.Xmrig.Config; config != nil && config.HTTP.AccessToken != "" {
+		opts = append(opts, xmrig.WithAccessToken(config.HTTP.AccessToken))
+	}
+
+	status := &v1alpha1.MoneroMiningProxyStatus{}
+
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if !IsPodReady(pod) || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		status.ReadyReplicas++
+
+		address := "http://" + pod.Status.PodIP + ":" + strconv.Itoa(int(XmrigHTTPPortNumber))
+		summary, err := xmrig.NewClient(address, opts...).ProxySummary(ctx)
+		if err != nil {
+			r.Log.Error(err, "proxy summary", "pod", pod.Name)
+			status.Message = fmt.Sprintf("pod '%s': %v", pod.Name, err)
+			continue
+		}
+
+		status.Miners += summary.Miners.Now
+		status.Upstreams += summary.Upstreams.Total
+		status.ActiveUpstreams += summary.Upstreams.Active
+		status.ErrorUpstreams += summary.Upstreams.Error
+		status.SharesAccepted += summary.Results.Accepted
+		status.SharesRejected += summary.Results.Rejected
+	}
+
+	miningSet.Status.Proxy = status
+	return nil
+}
+
+// RenderXmrigProxyConfig generates the contents of xmrig-proxy's
+// `config.json`, with the pools of the xmrig config as upstreams.
+//
+func RenderXmrigProxyConfig(t *MinerTemplate) ([]byte, error) {
+	var (
+		miningSet = t.MiningSet
+		config    = miningSet.Spec.Xmrig.Config
+	)
+
+	c := XmrigProxyJSONConfig{
+		DonateLevel: config.DonateLevel,
+		Mode:        miningSet.Spec.XmrigProxy.Mode,
+		Workers:     true,
+		Bind: []XmrigProxyJSONBind{
+			{
+				Host: "0.0.0.0",
+				Port: XmrigProxyStratumPortNumber,
+			},
+		},
+		HTTP: XmrigJSONHTTPConfig{
+			Enabled:     true,
+			Host:        "0.0.0.0",
+			Port:        XmrigHTTPPortNumber,
+			AccessToken: config.HTTP.AccessToken,
+			Restricted:  config.HTTP.Restricted,
+		},
+		Pools: make([]XmrigJSONPoolEntry, len(config.Pools)),
+	}
+
+	if c.Mode == "" {
+		c.Mode = v1alpha1.XmrigProxyModeNicehash
+	}
+
+	for i, pool := range config.Pools {
+		user, pass, err := t.PoolCredentials(pool, 0)
+		if err != nil {
+			return nil, fmt.Errorf("pool '%s': %w", pool.URL, err)
+		}
+
+		c.Pools[i] = XmrigJSONPoolEntry{
+			URL:       pool.URL,
+			User:      user,
+			Pass:      pass,
+			Keepalive: pool.Keepalive,
+			Nicehash:  pool.Nicehash,
+			TLS:       pool.TLS,
+			Daemon:    pool.Daemon,
+		}
+	}
+
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	return b, nil
+}
+
+// NewXmrigProxyConfigSecret renders xmrig-proxy's configuration into a
+// Secret, as it carries pool credentials.
+//
+func NewXmrigProxyConfigSecret(t *MinerTemplate) (*corev1.Secret, error) {
+	b, err := RenderXmrigProxyConfig(t)
+	if err != nil {
+		return nil, fmt.Errorf("render config: %w", err)
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      XmrigProxyName(t.MiningSet),
+			Namespace: t.MiningSet.Namespace,
+		},
+		Data: map[string][]byte{
+			XmrigProxyConfigFilename: b,
+		},
+	}, nil
+}
+
+func NewXmrigProxyDeployment(
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+	configHash string,
+) *appsv1.Deployment {
+	var (
+		proxy = miningSet.Spec.XmrigProxy
+		name  = XmrigProxyName(miningSet)
+	)
+
+	image := proxy.Image
+	if image == "" {
+		image = v1alpha1.DefaultXmrigProxyImage
+	}
+
+	replicas := proxy.Replicas
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	command := append([]string{
+		"xmrig-proxy",
+		"--config=" + XmrigProxyConfigVolumeMountPath + "/" + XmrigProxyConfigFilename,
+	}, proxy.Args...)
+
+	container := corev1.Container{
+		Name:    XmrigProxyContainerName,
+		Image:   image,
+		Command: command,
+		ReadinessProbe: &corev1.Probe{
+			PeriodSeconds:       15,
+			InitialDelaySeconds: 5,
+			FailureThreshold:    5,
+			Handler: corev1.Handler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromString(XmrigProxyStratumPortName),
+				},
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          XmrigProxyStratumPortName,
+				ContainerPort: int32(XmrigProxyStratumPortNumber),
+				Protocol:      corev1.ProtocolTCP,
+			},
+			{
+				Name:          XmrigHTTPPortName,
+				ContainerPort: int32(XmrigHTTPPortNumber),
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      XmrigProxyConfigVolumeName,
+				MountPath: XmrigProxyConfigVolumeMountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+
+	obj := &appsv1.Deployment{}
+
+	obj.TypeMeta = metav1.TypeMeta{
+		Kind:       "Deployment",
+		APIVersion: appsv1.SchemeGroupVersion.Identifier(),
+	}
+
+	obj.ObjectMeta = metav1.ObjectMeta{
+		Name:      name,
+		Namespace: miningSet.Namespace,
+	}
+
+	obj.Spec = appsv1.DeploymentSpec{
+		Replicas:             pointer.Int32Ptr(replicas),
+		RevisionHistoryLimit: pointer.Int32Ptr(0),
+		Selector: &metav1.LabelSelector{
+			MatchLabels: AppLabel(name),
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: AppLabel(name),
+				Annotations: map[string]string{
+					ConfigHashAnnotationKey: configHash,
+				},
+			},
+			Spec: corev1.PodSpec{
+				TerminationGracePeriodSeconds: pointer.Int64Ptr(60),
+				Containers:                    []corev1.Container{container},
+				Volumes: []corev1.Volume{
+					{
+						Name: XmrigProxyConfigVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{
+								SecretName: name,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return obj
+}
+
+func NewXmrigProxyService(miningSet *v1alpha1.MoneroMiningNodeSet) *corev1.Service {
+	obj := &corev1.Service{}
+
+	obj.TypeMeta = metav1.TypeMeta{
+		Kind:       "Service",
+		APIVersion: corev1.SchemeGroupVersion.Identifier(),
+	}
+
+	l := AppLabel(XmrigProxyName(miningSet))
+
+	obj.ObjectMeta = metav1.ObjectMeta{
+		Name:      XmrigProxyName(miningSet),
+		Namespace: miningSet.Namespace,
+		Labels:    l,
+	}
+
+	obj.Spec = corev1.ServiceSpec{
+		Selector: l,
+		Ports: []corev1.ServicePort{
+			{
+				Name:       XmrigProxyStratumPortName,
+				Port:       int32(XmrigProxyStratumPortNumber),
+				TargetPort: intstr.FromInt(int(XmrigProxyStratumPortNumber)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+			{
+				Name:       XmrigHTTPPortName,
+				Port:       int32(XmrigHTTPPortNumber),
+				TargetPort: intstr.FromInt(int(XmrigHTTPPortNumber)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		},
+	}
+
+	return obj
+}
@@ -0,0 +1,106 @@
+package reconciler
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+	"github.com/cirocosta/monero-operator/pkg/schedule"
+)
+
+// ResolveSchedule evaluates the schedule of the mining set at `now`,
+// recording in the status whether the miners should be running and when
+// that's going to change.
+//
+func (r *MoneroMiningNodeSetReconciler) ResolveSchedule(
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+	now time.Time,
+) error {
+	spec := miningSet.Spec.Schedule
+	if spec == nil {
+		miningSet.Status.Schedule = nil
+		return nil
+	}
+
+	tz := spec.TimeZone
+	if tz == "" {
+		tz = "UTC"
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("load location '%s': %w", tz, err)
+	}
+
+	windows := make([]schedule.Window, len(spec.Windows))
+	for idx, w := range spec.Windows {
+		windows[idx], err = schedule.NewWindow(w.Start, w.Duration.Duration)
+		if err != nil {
+			return fmt.Errorf("window %d: %w", idx, err)
+		}
+	}
+
+	active, next := schedule.State(windows, now.In(loc))
+
+	status := &v1alpha1.MoneroMiningScheduleStatus{
+		Active: active,
+	}
+
+	if !next.IsZero() {
+		status.NextTransitionTime = &metav1.Time{Time: next}
+	}
+
+	miningSet.Status.Schedule = status
+
+	return nil
+}
+
+// MinersScheduled tells whether, according to the schedule (if any), the
+// miners should be running.
+//
+func MinersScheduled(miningSet *v1alpha1.MoneroMiningNodeSet) bool {
+	return miningSet.Status.Schedule == nil || miningSet.Status.Schedule.Active
+}
+
+// DesiredMiners is the number of miners that should be running at the
+// moment: none outside of the schedule or while the node set solo mined
+// against can't give them work, and otherwise those not yielded.
+//
+func DesiredMiners(miningSet *v1alpha1.MoneroMiningNodeSet) uint32 {
+	if !MinersScheduled(miningSet) || SoloMiningHeld(miningSet) {
+		return 0
+	}
+
+	yielded := MinersYielded(miningSet)
+	if yielded > miningSet.Spec.Replicas {
+		return 0
+	}
+
+	return miningSet.Spec.Replicas - yielded
+}
+
+// RequeueAfter is how long to wait before reconciling the mining set again:
+// the regular status interval, or less if the schedule is about to
+// transition.
+//
+func RequeueAfter(miningSet *v1alpha1.MoneroMiningNodeSet, now time.Time) time.Duration {
+	interval := MiningStatusInterval
+
+	if s := miningSet.Status.Schedule; s != nil && s.NextTransitionTime != nil {
+		// a bit past the boundary so that it's already crossed by the
+		// time we get to evaluate it.
+		//
+		until := s.NextTransitionTime.Sub(now) + time.Second
+		if until < interval {
+			interval = until
+		}
+	}
+
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	return interval
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/valyala/fasttemplate"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -19,6 +23,31 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+	"github.com/cirocosta/monero-operator/pkg/metrics"
+	"github.com/cirocosta/monero-operator/pkg/xmrig"
+)
+
+const (
+	// MiningStatusInterval is how often the miners get polled for their
+	// hashrate.
+	//
+	MiningStatusInterval = 30 * time.Second
+
+	// MinerObservationConcurrency is how many miners get polled for their
+	// hashrate at once.
+	//
+	MinerObservationConcurrency = 8
+
+	// MinerObservationTimeout bounds how long polling all of the miners of
+	// a set may take.
+	//
+	MinerObservationTimeout = 15 * time.Second
+
+	// SoloMiningConditionType is the type of the condition reporting on
+	// whether the node set that the miners solo mine against can give
+	// them work.
+	//
+	SoloMiningConditionType = "SoloMining"
 )
 
 type MoneroMiningNodeSetReconciler struct {
@@ -30,6 +59,7 @@ func (r *MoneroMiningNodeSetReconciler) Reconcile(ctx context.Context, req ctrl.
 	miningSet, err := r.GetMoneroMiningNodeSet(ctx, req.Name, req.Namespace)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			metrics.DeleteMiningSet(req.Namespace, req.Name)
 			return EmptyResult(), nil
 		}
 
@@ -41,93 +71,544 @@ func (r *MoneroMiningNodeSetReconciler) Reconcile(ctx context.Context, req ctrl.
 		return EmptyResult(), fmt.Errorf("reconcile moneronodeset: %w", err)
 	}
 
-	return EmptyResult(), nil
+	return ctrl.Result{RequeueAfter: RequeueAfter(miningSet, time.Now())}, nil
 }
 
 func (r *MoneroMiningNodeSetReconciler) ReconcileMoneroMiningNodeSet(
 	ctx context.Context,
 	miningSet *v1alpha1.MoneroMiningNodeSet,
 ) error {
+	if miningSet.Spec.Solo != nil && miningSet.Spec.P2PoolRef != nil {
+		return fmt.Errorf("solo and p2poolRef are mutually exclusive")
+	}
+
+	tmpl := r.NewMinerTemplate(ctx, miningSet)
+
+	if err := r.ResolveSchedule(miningSet, time.Now()); err != nil {
+		return fmt.Errorf("resolve schedule: %w", err)
+	}
+
+	if miningSet.Spec.Budget != nil {
+		if err := r.EnsureMiningPriorityClass(ctx); err != nil {
+			return fmt.Errorf("ensure priorityclass: %w", err)
+		}
+	}
+
+	r.ResolveYield(ctx, miningSet)
+
+	if miningSet.Spec.P2PoolRef != nil {
+		if err := r.ResolveP2Pool(ctx, miningSet); err != nil {
+			return fmt.Errorf("resolve p2pool: %w", err)
+		}
+	}
+
+	if len(miningSet.Spec.Pools) > 0 {
+		if err := r.ResolvePools(ctx, tmpl); err != nil {
+			return fmt.Errorf("resolve pools: %w", err)
+		}
+	} else {
+		miningSet.Status.Pools = nil
+		miningSet.Status.ActivePool = ""
+	}
+
+	if miningSet.Spec.Solo != nil {
+		if err := r.ResolveSolo(ctx, miningSet); err != nil {
+			return fmt.Errorf("resolve solo: %w", err)
+		}
+	} else {
+		meta.RemoveStatusCondition(&miningSet.Status.Conditions, SoloMiningConditionType)
+	}
+
+	if miningSet.Spec.XmrigProxy != nil {
+		if err := r.ReconcileXmrigProxy(ctx, tmpl); err != nil {
+			return fmt.Errorf("reconcile xmrig-proxy: %w", err)
+		}
+	} else {
+		if err := r.PruneXmrigProxy(ctx, miningSet); err != nil {
+			return fmt.Errorf("prune xmrig-proxy: %w", err)
+		}
+
+		miningSet.Status.Proxy = nil
+	}
+
+	if miningSet.Spec.Xmrig.Config != nil {
+		secret, err := NewXmrigConfigSecret(tmpl)
+		if err != nil {
+			return fmt.Errorf("new xmrig config secret: %w", err)
+		}
+
+		r.SetOwnerRef(miningSet, secret)
+
+		if err := r.Apply(ctx, secret); err != nil {
+			return fmt.Errorf("apply config secret: %w", err)
+		}
+	}
+
+	statefulSet, err := r.AssembleStatefulSet(tmpl)
+	if err != nil {
+		return fmt.Errorf("assemble statefulset: %w", err)
+	}
+
+	if err := r.Apply(ctx, statefulSet); err != nil {
+		return fmt.Errorf("apply statefulset: %w", err)
+	}
+
+	if err := r.PruneDeployments(ctx, miningSet); err != nil {
+		return fmt.Errorf("prune deployments: %w", err)
+	}
+
+	if err := r.PruneLegacyStatefulSet(ctx, miningSet); err != nil {
+		return fmt.Errorf("prune legacy statefulset: %w", err)
+	}
+
+	if err := r.UpdateStatus(ctx, miningSet); err != nil {
+		return fmt.Errorf("update status: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveSolo points the miners at the unrestricted RPC interface of the
+// node set they solo mine against (replacing any pools), paying out to the
+// configured address.
+//
+// Whether the miners can get block templates from the node set is reported
+// through the SoloMining condition: the node set must have its unrestricted
+// RPC interface enabled without login (as xmrig can't provide one), and be
+// synchronized, with no point in mining until then. While that's not the
+// case, the miners are scaled down (see DesiredMiners).
+//
+func (r *MoneroMiningNodeSetReconciler) ResolveSolo(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) error {
+	solo := miningSet.Spec.Solo
+
+	namespace := solo.NodeSetRef.Namespace
+	if namespace == "" {
+		namespace = miningSet.Namespace
+	}
+
+	nodeSet := &v1alpha1.MoneroNodeSet{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      solo.NodeSetRef.Name,
+		Namespace: namespace,
+	}, nodeSet); err != nil {
+		return fmt.Errorf("get moneronodeset %s/%s: %w", namespace, solo.NodeSetRef.Name, err)
+	}
+
+	nodeSet.ApplyDefaults()
 
-	deployments, err := r.AssembleDeployments(miningSet)
+	rpc, err := EffectiveUnrestrictedRPC(ctx, r.Client, nodeSet)
 	if err != nil {
-		return fmt.Errorf("assemble deployments: %w", err)
+		return fmt.Errorf("effective unrestricted rpc: %w", err)
 	}
 
-	for _, deployment := range deployments {
-		if err := r.Apply(ctx, deployment); err != nil {
-			return fmt.Errorf("apply: %w", err)
+	address := solo.Address
+	if ref := solo.AddressSecretRef; ref != nil {
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{
+			Name:      ref.Name,
+			Namespace: miningSet.Namespace,
+		}, secret); err != nil {
+			return fmt.Errorf("get address secret: %w", err)
 		}
+
+		address = string(secret.Data[ref.Key])
+	}
+
+	if address == "" {
+		return fmt.Errorf("solo mining requires a payout address")
+	}
+
+	if miningSet.Spec.Xmrig.Config == nil {
+		miningSet.Spec.Xmrig.Config = &v1alpha1.XmrigConfigFile{}
+	}
+
+	miningSet.Spec.Xmrig.Config.Pools = []v1alpha1.XmrigPool{
+		{
+			URL:    DaemonAddress(nodeSet, true),
+			User:   address,
+			Daemon: true,
+		},
+	}
+
+	condition := metav1.Condition{
+		Type:    SoloMiningConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Synchronized",
+		Message: fmt.Sprintf("mining against moneronodeset '%s'", nodeSet.Name),
+	}
+
+	switch {
+	case !rpc.Usable():
+		condition = rpc.UnusableCondition(SoloMiningConditionType, nodeSet)
+	case !nodeSet.Status.Synchronized:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "WaitingForSync"
+		condition.Message = fmt.Sprintf("moneronodeset '%s' not synchronized", nodeSet.Name)
+	}
+
+	meta.SetStatusCondition(&miningSet.Status.Conditions, condition)
+
+	return nil
+}
+
+// SoloMiningHeld tells whether the miners of a set solo mining against a
+// node set are being held back as it can't give them work.
+//
+func SoloMiningHeld(miningSet *v1alpha1.MoneroMiningNodeSet) bool {
+	return miningSet.Spec.Solo != nil &&
+		!meta.IsStatusConditionTrue(miningSet.Status.Conditions, SoloMiningConditionType)
+}
+
+// ResolveP2Pool points the miners at the stratum port of the referenced
+// MoneroP2Pool (replacing any pools), using `<set>-<id>` as worker names.
+//
+func (r *MoneroMiningNodeSetReconciler) ResolveP2Pool(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) error {
+	ref := miningSet.Spec.P2PoolRef
+
+	pool := &v1alpha1.MoneroP2Pool{}
+	pool.Name = ref.Name
+	pool.Namespace = ref.Namespace
+	if pool.Namespace == "" {
+		pool.Namespace = miningSet.Namespace
+	}
+
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      pool.Name,
+		Namespace: pool.Namespace,
+	}, pool); err != nil {
+		return fmt.Errorf("get monerop2pool %s/%s: %w", pool.Namespace, pool.Name, err)
+	}
+
+	if miningSet.Spec.Xmrig.Config == nil {
+		miningSet.Spec.Xmrig.Config = &v1alpha1.XmrigConfigFile{}
+	}
+
+	miningSet.Spec.Xmrig.Config.Pools = []v1alpha1.XmrigPool{
+		{
+			URL:       P2PoolStratumAddress(pool),
+			User:      miningSet.Name + "-$(id)",
+			Keepalive: true,
+		},
 	}
 
 	return nil
 }
 
-func (r *MoneroMiningNodeSetReconciler) AssembleDeployments(
+// OwnedDeployments lists the Deployments controlled by the mining set, which
+// is how miners used to be run (one Deployment per miner).
+//
+func (r *MoneroMiningNodeSetReconciler) OwnedDeployments(
+	ctx context.Context,
 	miningSet *v1alpha1.MoneroMiningNodeSet,
 ) ([]*appsv1.Deployment, error) {
-	deployments := make([]*appsv1.Deployment, miningSet.Spec.Replicas)
+	list := &appsv1.DeploymentList{}
+	if err := r.Client.List(ctx, list,
+		client.InNamespace(miningSet.Namespace),
+	); err != nil {
+		return nil, fmt.Errorf("list: %w", err)
+	}
 
-	var err error
-	for i := 0; i < int(miningSet.Spec.Replicas); i++ {
-		deployments[i], err = r.AssembleMiningDeployment(miningSet, i)
-		if err != nil {
-			return nil, fmt.Errorf("assemble moneronodeset '%d': %w", i, err)
+	owned := []*appsv1.Deployment{}
+	for idx := range list.Items {
+		deployment := &list.Items[idx]
+		if !metav1.IsControlledBy(deployment, miningSet) {
+			continue
+		}
+
+		owned = append(owned, deployment)
+	}
+
+	return owned, nil
+}
+
+// PruneDeployments removes the Deployments that miners were run as before
+// being moved to a single StatefulSet, so that no miner is left behind
+// (leaving xmrig-proxy's alone).
+//
+func (r *MoneroMiningNodeSetReconciler) PruneDeployments(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) error {
+	owned, err := r.OwnedDeployments(ctx, miningSet)
+	if err != nil {
+		return fmt.Errorf("owned deployments: %w", err)
+	}
+
+	for _, deployment := range owned {
+		if deployment.Name == XmrigProxyName(miningSet) {
+			continue
+		}
+
+		r.Log.Info("deleting legacy miner", "deployment", deployment.Name)
+
+		if err := r.Client.Delete(ctx, deployment,
+			client.PropagationPolicy(metav1.DeletePropagationForeground),
+		); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete '%s': %w", deployment.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// PruneLegacyStatefulSet removes the StatefulSet that miners were run as
+// before it got suffixed, as long as it's controlled by the mining set (a
+// MoneroNodeSet with the same name has its own under that name).
+//
+func (r *MoneroMiningNodeSetReconciler) PruneLegacyStatefulSet(
+	ctx context.Context,
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+) error {
+	statefulSet := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      LegacyMinersStatefulSetName(miningSet),
+		Namespace: miningSet.Namespace,
+	}, statefulSet); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
 		}
+
+		return fmt.Errorf("get statefulset: %w", err)
+	}
+
+	if !metav1.IsControlledBy(statefulSet, miningSet) {
+		return nil
 	}
 
-	return deployments, nil
+	r.Log.Info("deleting legacy miners", "statefulset", statefulSet.Name)
+
+	if err := r.Client.Delete(ctx, statefulSet,
+		client.PropagationPolicy(metav1.DeletePropagationForeground),
+	); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete '%s': %w", statefulSet.Name, err)
+	}
+
+	return nil
 }
 
-func (r *MoneroMiningNodeSetReconciler) AssembleMiningDeployment(
+// UpdateStatus reports the number of desired miners (none outside of the
+// schedule) and how many of those are ready.
+//
+func (r *MoneroMiningNodeSetReconciler) UpdateStatus(
+	ctx context.Context,
 	miningSet *v1alpha1.MoneroMiningNodeSet,
-	idx int,
-) (*appsv1.Deployment, error) {
-	command := append([]string{
-		"xmrig",
-	}, miningSet.Spec.Xmrig.Args...)
-
-	for i, arg := range command {
-		t := fasttemplate.New(arg, "$(", ")")
-		s := t.ExecuteString(map[string]interface{}{
-			"id": strconv.Itoa(idx),
+) error {
+	statefulSet := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      MinersStatefulSetName(miningSet),
+		Namespace: miningSet.Namespace,
+	}, statefulSet); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("get statefulset: %w", err)
+	}
+
+	ready := uint32(statefulSet.Status.ReadyReplicas)
+
+	desired := DesiredMiners(miningSet)
+	if ready > desired {
+		ready = desired
+	}
+
+	miningSet.Status.Replicas = desired
+	miningSet.Status.ReadyReplicas = ready
+
+	if err := r.ObserveMiners(ctx, miningSet); err != nil {
+		return fmt.Errorf("observe miners: %w", err)
+	}
+
+	if miningSet.Spec.XmrigProxy != nil {
+		if err := r.ObserveXmrigProxy(ctx, miningSet); err != nil {
+			return fmt.Errorf("observe xmrig-proxy: %w", err)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "Succeeded",
+		Message: fmt.Sprintf("%d/%d miners ready", ready, desired),
+	}
+
+	solo := meta.FindStatusCondition(miningSet.Status.Conditions, SoloMiningConditionType)
+
+	if SoloMiningHeld(miningSet) && solo != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = solo.Reason
+		condition.Message = "miners scaled down: " + solo.Message
+	} else if !MinersScheduled(miningSet) {
+		condition.Reason = "OutsideSchedule"
+		condition.Message = "miners scaled down until the next schedule window"
+	} else if MinersYielded(miningSet) > 0 {
+		condition.Reason = "Yielding"
+		condition.Message = fmt.Sprintf("%d/%d miners ready, %d yielded to other workloads",
+			ready, desired, MinersYielded(miningSet))
+	} else if ready != desired {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Progressing"
+	}
+
+	meta.SetStatusCondition(&miningSet.Status.Conditions, condition)
+
+	if err := r.Client.Status().Update(ctx, miningSet); err != nil {
+		return fmt.Errorf("status update: %w", err)
+	}
+
+	return nil
+}
+
+// AssembleStatefulSet creates the StatefulSet that all of the miners in the
+// set run as, with the ordinal of each pod being its index.
+//
+func (r *MoneroMiningNodeSetReconciler) AssembleStatefulSet(
+	t *MinerTemplate,
+) (*appsv1.StatefulSet, error) {
+	var (
+		miningSet = t.MiningSet
+		args      = append([]string{"xmrig"}, miningSet.Spec.Xmrig.Args...)
+		volumes   = []corev1.Volume{}
+		mounts    = []corev1.VolumeMount{}
+		digest    string
+	)
+
+	if miningSet.Spec.Xmrig.Config != nil {
+		secret, err := NewXmrigConfigSecret(t)
+		if err != nil {
+			return nil, fmt.Errorf("new xmrig config secret: %w", err)
+		}
+
+		digest += SecretDigest(secret)
+
+		// each pod picks its own file, named after it.
+		//
+		args = append(args,
+			"--config="+XmrigConfigVolumeMountPath+"/$(pod.name)"+XmrigConfigFileExtension,
+		)
+
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      XmrigConfigVolumeName,
+			MountPath: XmrigConfigVolumeMountPath,
+			ReadOnly:  true,
+		})
+
+		volumes = append(volumes, corev1.Volume{
+			Name: XmrigConfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: XmrigConfigSecretName(miningSet),
+				},
+			},
 		})
+	} else {
+		args = append(args,
+			"--http-host=0.0.0.0",
+			"--http-port="+strconv.Itoa(int(XmrigHTTPPortNumber)),
+		)
+
+		args = append(args, XmrigPerformanceArgs(miningSet)...)
+	}
+
+	command, err := t.Command(args)
+	if err != nil {
+		return nil, fmt.Errorf("interpolate args: %w", err)
+	}
 
-		command[i] = s
+	envDigest, err := t.EnvDigest(command.Env)
+	if err != nil {
+		return nil, fmt.Errorf("env digest: %w", err)
 	}
 
+	digest += envDigest
+
 	container := corev1.Container{
-		Name:    "xmrig",
-		Image:   miningSet.Spec.Xmrig.Image,
-		Command: command,
+		Name:         XmrigContainerName,
+		Image:        miningSet.Spec.Xmrig.Image,
+		Command:      command.Command,
+		Env:          command.Env,
+		VolumeMounts: mounts,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          XmrigHTTPPortName,
+				ContainerPort: int32(XmrigHTTPPortNumber),
+			},
+		},
+	}
+
+	annotations := map[string]string{}
+	if digest != "" {
+		annotations[ConfigHashAnnotationKey] = ConfigHash(digest)
+	}
+
+	podSpec := corev1.PodSpec{
+		TerminationGracePeriodSeconds: pointer.Int64Ptr(60),
+		Containers:                    []corev1.Container{container},
+		Volumes:                       volumes,
+		TopologySpreadConstraints: NewTopologySpreadConstraints(
+			miningSet.Spec.TopologySpreadConstraints,
+			MinerLabels(miningSet),
+		),
 	}
 
-	o := &appsv1.Deployment{
+	if miningSet.Spec.HardAntiAffinity {
+		podSpec.Affinity = NewHardAntiAffinity(MinerLabels(miningSet))
+	}
+
+	ApplyPerformance(miningSet, &podSpec)
+	ApplyBudget(miningSet, &podSpec)
+
+	if command.Shell {
+		podSpec.InitContainers = []corev1.Container{MinerShellInitContainer()}
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: MinerShellVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      MinerShellVolumeName,
+			MountPath: MinerShellVolumeMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	o := &appsv1.StatefulSet{
 		TypeMeta: metav1.TypeMeta{
-			Kind:       "Deployment",
+			Kind:       "StatefulSet",
 			APIVersion: "apps/v1",
 		},
 
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.DeploymentName(miningSet, idx),
+			Name:      MinersStatefulSetName(miningSet),
 			Namespace: miningSet.Namespace,
 		},
 
-		Spec: appsv1.DeploymentSpec{
-			Replicas: pointer.Int32Ptr(1),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(int32(DesiredMiners(miningSet))),
 			Selector: &metav1.LabelSelector{
-				MatchLabels: AppLabel(miningSet.Name),
+				MatchLabels: MinerLabels(miningSet),
 			},
-			RevisionHistoryLimit: pointer.Int32Ptr(0),
+
+			// miners don't depend on each other, so there's no
+			// reason for scaling one at a time.
+			//
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+
+			// required, even though miners aren't addressed
+			// individually through a headless service.
+			//
+			ServiceName: MinersStatefulSetName(miningSet),
+
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: AppLabel(miningSet.Name),
+					Labels:      MinerLabels(miningSet),
+					Annotations: annotations,
 				},
 
-				Spec: corev1.PodSpec{
-					TerminationGracePeriodSeconds: pointer.Int64Ptr(60),
-					Containers:                    []corev1.Container{container},
-				},
+				Spec: podSpec,
 			},
 		},
 	}
@@ -137,11 +618,228 @@ func (r *MoneroMiningNodeSetReconciler) AssembleMiningDeployment(
 	return o, nil
 }
 
-func (r *MoneroMiningNodeSetReconciler) DeploymentName(
+// ObserveMiners gathers, through xmrig's HTTP API, what each ready miner is
+// up to, reporting it in the status and as metrics.
+//
+// Miners are polled concurrently (up to MinerObservationConcurrency at a
+// time, within MinerObservationTimeout overall), with those that can't be
+// reached left out rather than failing the whole reconciliation.
+//
+func (r *MoneroMiningNodeSetReconciler) ObserveMiners(
+	ctx context.Context,
 	miningSet *v1alpha1.MoneroMiningNodeSet,
-	idx int,
-) string {
-	return miningSet.Name + "-" + strconv.Itoa(idx)
+) error {
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods,
+		client.InNamespace(miningSet.Namespace),
+		client.MatchingLabels(MinerLabels(miningSet)),
+	); err != nil {
+		return fmt.Errorf("list pods: %w", err)
+	}
+
+	opts := []xmrig.ClientOption{}
+	if config := miningSet.Spec.Xmrig.Config; config != nil && config.HTTP.AccessToken != "" {
+		opts = append(opts, xmrig.WithAccessToken(config.HTTP.AccessToken))
+	}
+
+	type miner struct {
+		idx     int
+		pod     *corev1.Pod
+		summary *xmrig.Summary
+	}
+
+	miners := []*miner{}
+	candidates := map[int]bool{}
+
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if !IsPodReady(pod) || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		minerIdx, err := MinerOrdinal(miningSet, pod.Name)
+		if err != nil || minerIdx >= int(miningSet.Spec.Replicas) || candidates[minerIdx] {
+			continue
+		}
+
+		candidates[minerIdx] = true
+		miners = append(miners, &miner{idx: minerIdx, pod: pod})
+	}
+
+	// miners get polled a few at a time, with the whole pass bounded so
+	// that unreachable ones can't hold up the reconciliation.
+	//
+	ctx, cancel := context.WithTimeout(ctx, MinerObservationTimeout)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, MinerObservationConcurrency)
+	)
+
+	for _, m := range miners {
+		wg.Add(1)
+		go func(m *miner) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				r.Log.Error(ctx.Err(), "summary", "pod", m.pod.Name)
+				return
+			}
+
+			address := "http://" + m.pod.Status.PodIP + ":" + strconv.Itoa(int(XmrigHTTPPortNumber))
+			summary, err := xmrig.NewClient(address, opts...).Summary(ctx)
+			if err != nil {
+				r.Log.Error(err, "summary", "pod", m.pod.Name)
+				return
+			}
+
+			m.summary = summary
+		}(m)
+	}
+
+	wg.Wait()
+
+	var (
+		total    v1alpha1.MoneroMiningHashrate
+		statuses = []v1alpha1.MoneroMinerStatus{}
+		observed = map[int]bool{}
+		perf     = v1alpha1.MoneroMiningPerformanceStatus{}
+	)
+
+	for _, m := range miners {
+		if m.summary == nil {
+			continue
+		}
+
+		var (
+			pod      = m.pod
+			minerIdx = m.idx
+			summary  = m.summary
+		)
+
+		observed[minerIdx] = true
+
+		allocated, hugePages := summary.HugePagesUsage()
+
+		status := v1alpha1.MoneroMinerStatus{
+			Index: uint32(minerIdx),
+			Pod:   pod.Name,
+			Hashrate: v1alpha1.MoneroMiningHashrate{
+				TenSeconds:     uint64(summary.HashrateAt(0)),
+				SixtySeconds:   uint64(summary.HashrateAt(1)),
+				FifteenMinutes: uint64(summary.HashrateAt(2)),
+			},
+			SharesAccepted: summary.Connection.Accepted,
+			SharesRejected: summary.Connection.Rejected,
+			Pool:           summary.Connection.Pool,
+			Uptime:         metav1.Duration{Duration: time.Duration(summary.Uptime) * time.Second},
+			HugePages: v1alpha1.MoneroMinerHugePages{
+				Allocated: allocated,
+				Total:     hugePages,
+			},
+			MSR: MSRApplied(pod),
+		}
+
+		if hugePages > 0 && allocated == hugePages {
+			perf.HugePagesMiners++
+		}
+
+		if status.MSR != "" {
+			perf.MSRMiners++
+		}
+
+		total.TenSeconds += status.Hashrate.TenSeconds
+		total.SixtySeconds += status.Hashrate.SixtySeconds
+		total.FifteenMinutes += status.Hashrate.FifteenMinutes
+		statuses = append(statuses, status)
+
+		metrics.SetMiner(miningSet.Namespace, miningSet.Name, minerIdx, metrics.MinerStats{
+			Hashrate: []float64{
+				summary.HashrateAt(0),
+				summary.HashrateAt(1),
+				summary.HashrateAt(2),
+			},
+			SharesAccepted: summary.Connection.Accepted,
+			SharesRejected: summary.Connection.Rejected,
+			UptimeSeconds:  summary.Uptime,
+		})
+	}
+
+	for _, status := range miningSet.Status.Miners {
+		if !observed[int(status.Index)] {
+			metrics.DeleteMiner(miningSet.Namespace, miningSet.Name, int(status.Index))
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Index < statuses[j].Index
+	})
+
+	miningSet.Status.Hashrate = total
+	miningSet.Status.Miners = statuses
+
+	miningSet.Status.Performance = nil
+	if miningSet.Spec.Performance != nil {
+		miningSet.Status.Performance = &perf
+	}
+
+	return nil
+}
+
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// MinersStatefulSetName is the name of the StatefulSet that the miners run
+// as, suffixed so that it doesn't collide with the one of a MoneroNodeSet
+// with the same name.
+//
+func MinersStatefulSetName(miningSet *v1alpha1.MoneroMiningNodeSet) string {
+	return miningSet.Name + "-miners"
+}
+
+// LegacyMinersStatefulSetName is the name of the StatefulSet that earlier
+// versions of the operator ran the miners as.
+//
+func LegacyMinersStatefulSetName(miningSet *v1alpha1.MoneroMiningNodeSet) string {
+	return miningSet.Name
+}
+
+// MinerPodName is the name of the pod of the miner at `idx`.
+//
+func MinerPodName(miningSet *v1alpha1.MoneroMiningNodeSet, idx int) string {
+	return MinersStatefulSetName(miningSet) + "-" + strconv.Itoa(idx)
+}
+
+// MinerOrdinal extracts the index of a miner out of the name of its pod.
+//
+func MinerOrdinal(miningSet *v1alpha1.MoneroMiningNodeSet, podName string) (int, error) {
+	prefix := MinersStatefulSetName(miningSet) + "-"
+	if !strings.HasPrefix(podName, prefix) {
+		return 0, fmt.Errorf("pod '%s' not part of '%s'", podName, miningSet.Name)
+	}
+
+	return strconv.Atoi(strings.TrimPrefix(podName, prefix))
+}
+
+// MinerLabels identifies the pods of the miners in a set, distinguishing them
+// from those of other kinds of sets with the same name.
+//
+func MinerLabels(miningSet *v1alpha1.MoneroMiningNodeSet) map[string]string {
+	labels := AppLabel(miningSet.Name)
+	labels[MiningSetLabelKey] = miningSet.Name
+
+	return labels
 }
 
 func (r *MoneroMiningNodeSetReconciler) GetMoneroMiningNodeSet(
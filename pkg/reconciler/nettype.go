@@ -0,0 +1,90 @@
+package reconciler
+
+import (
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+)
+
+// NetworkPorts holds the ports used by monerod (and the Tor hidden service in
+// front of it) for a given Monero network.
+//
+type NetworkPorts struct {
+	P2P        uint16
+	RPC        uint16
+	Restricted uint16
+	TorP2P     uint16
+	ZMQPub     uint16
+
+	// NodePortBase is the NodePort that the port ending in `000` would map
+	// to, keeping the NodePorts of different networks apart.
+	//
+	NodePortBase int32
+}
+
+// NodePort maps a port to a port in the NodePort range (30000-32767).
+//
+func (p NetworkPorts) NodePort(port uint16) int32 {
+	return p.NodePortBase + int32(port%1000)
+}
+
+var networkPorts = map[string]NetworkPorts{
+	v1alpha1.NetworkMainnet: {
+		P2P:          P2PPortNumber,
+		RPC:          RPCPortNumber,
+		Restricted:   RestrictedPortNumber,
+		TorP2P:       TorP2PPortNumber,
+		ZMQPub:       ZMQPubPortNumber,
+		NodePortBase: 30000,
+	},
+	v1alpha1.NetworkTestnet: {
+		P2P:          28080,
+		RPC:          28081,
+		Restricted:   28089,
+		TorP2P:       28083,
+		ZMQPub:       28084,
+		NodePortBase: 31000,
+	},
+	v1alpha1.NetworkStagenet: {
+		P2P:          38080,
+		RPC:          38081,
+		Restricted:   38089,
+		TorP2P:       38083,
+		ZMQPub:       38084,
+		NodePortBase: 32000,
+	},
+	v1alpha1.NetworkRegtest: {
+		P2P:          P2PPortNumber,
+		RPC:          RPCPortNumber,
+		Restricted:   RestrictedPortNumber,
+		TorP2P:       TorP2PPortNumber,
+		ZMQPub:       ZMQPubPortNumber,
+		NodePortBase: 30500,
+	},
+}
+
+// Ports retrieves the set of ports for the network a node set is part of,
+// defaulting to mainnet.
+//
+func Ports(nodeSet *v1alpha1.MoneroNodeSet) NetworkPorts {
+	ports, found := networkPorts[nodeSet.Spec.Monerod.Network]
+	if !found {
+		return networkPorts[v1alpha1.NetworkMainnet]
+	}
+
+	return ports
+}
+
+// NetworkFlags are the flags that monerod needs to join the network the node
+// set is part of.
+//
+func NetworkFlags(nodeSet *v1alpha1.MoneroNodeSet) []string {
+	switch nodeSet.Spec.Monerod.Network {
+	case v1alpha1.NetworkTestnet:
+		return []string{"--testnet"}
+	case v1alpha1.NetworkStagenet:
+		return []string{"--stagenet"}
+	case v1alpha1.NetworkRegtest:
+		return []string{"--regtest"}
+	}
+
+	return []string{}
+}
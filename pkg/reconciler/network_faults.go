@@ -0,0 +1,464 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+)
+
+const (
+	NetworkLabelKey = "utxo.com.br/network"
+	FaultLabelKey   = "utxo.com.br/fault"
+
+	// NamespaceNameLabelKey is the label that Kubernetes sets on every
+	// namespace with its name.
+	//
+	NamespaceNameLabelKey = "kubernetes.io/metadata.name"
+
+	// FaultRefreshInterval is how often the NetworkPolicies of active
+	// faults get refreshed, following the addresses of the pods that they
+	// cut off.
+	//
+	FaultRefreshInterval = 30 * time.Second
+
+	// FaultRestartRefreshInterval is how soon after restarting the
+	// members of a partition its NetworkPolicies get refreshed, following
+	// the addresses of the new pods.
+	//
+	FaultRestartRefreshInterval = 5 * time.Second
+)
+
+// ReconcileFaults brings the set of NetworkPolicies that partition the
+// members of the network in line with the faults that should be active at
+// this moment, recording the timeline of each fault in the network's status.
+//
+// Faults that can't be put in place are recorded as invalid (with the
+// reason) and skipped, leaving the others unaffected.
+//
+// The returned duration indicates when the next fault transition (start or
+// heal) is due, or zero if there's nothing else scheduled.
+//
+func (r *MoneroNetworkReconciler) ReconcileFaults(
+	ctx context.Context,
+	network *v1alpha1.MoneroNetwork,
+) (time.Duration, error) {
+	var (
+		now          = time.Now()
+		requeueAfter time.Duration
+		policies     = []*networkingv1.NetworkPolicy{}
+		statuses     = []v1alpha1.MoneroNetworkFaultStatus{}
+		restarts     = []string{}
+	)
+
+	for _, fault := range network.Spec.Faults {
+		status := r.FaultStatus(network, fault.Name)
+
+		if err := r.ValidateFault(network, fault); err != nil {
+			status.Phase = v1alpha1.MoneroNetworkFaultPhaseInvalid
+			status.Message = err.Error()
+
+			statuses = append(statuses, status)
+			continue
+		}
+
+		status.Message = ""
+
+		// anchoring the timeline to when the fault was first seen
+		// (rather than to the creation of the network) lets faults be
+		// added to networks that have been running for a while.
+		//
+		if status.ObservedTime == nil {
+			status.ObservedTime = &metav1.Time{Time: now}
+		}
+
+		start := status.ObservedTime.Add(fault.StartAfter.Duration)
+		end := start.Add(fault.Duration.Duration)
+
+		var next time.Duration
+
+		switch {
+		case now.Before(start):
+			status.Phase = v1alpha1.MoneroNetworkFaultPhasePending
+			next = start.Sub(now)
+
+		case now.Before(end):
+			starting := status.StartTime == nil
+			if starting {
+				status.StartTime = &metav1.Time{Time: now}
+			}
+
+			faultPolicies, err := r.AssembleFaultNetworkPolicies(ctx, network, fault, starting)
+			if err != nil {
+				return 0, fmt.Errorf("assemble networkpolicies '%s': %w", fault.Name, err)
+			}
+
+			status.Phase = v1alpha1.MoneroNetworkFaultPhaseActive
+			policies = append(policies, faultPolicies...)
+
+			next = end.Sub(now)
+			if next > FaultRefreshInterval {
+				next = FaultRefreshInterval
+			}
+
+			if starting {
+				for _, group := range fault.Partition {
+					restarts = append(restarts, group...)
+				}
+
+				if next > FaultRestartRefreshInterval {
+					next = FaultRestartRefreshInterval
+				}
+			}
+
+		default:
+			if status.HealTime == nil {
+				status.HealTime = &metav1.Time{Time: now}
+			}
+
+			status.Phase = v1alpha1.MoneroNetworkFaultPhaseHealed
+		}
+
+		if next > 0 && (requeueAfter == 0 || next < requeueAfter) {
+			requeueAfter = next
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	for _, policy := range policies {
+		r.SetOwnerRef(network, policy)
+
+		if err := r.Apply(ctx, policy); err != nil {
+			return 0, fmt.Errorf("apply networkpolicy '%s': %w", policy.Name, err)
+		}
+	}
+
+	if err := r.PruneFaultNetworkPolicies(ctx, network, policies); err != nil {
+		return 0, fmt.Errorf("prune networkpolicies: %w", err)
+	}
+
+	if len(restarts) > 0 {
+		if err := r.RestartMembers(ctx, network, restarts); err != nil {
+			return 0, fmt.Errorf("restart members: %w", err)
+		}
+	}
+
+	network.Status.Faults = statuses
+	return requeueAfter, nil
+}
+
+// ValidateFault ensures that a fault only refers to members of the network
+// and that no member is part of more than one side of the partition.
+//
+func (r *MoneroNetworkReconciler) ValidateFault(
+	network *v1alpha1.MoneroNetwork,
+	fault v1alpha1.MoneroNetworkFault,
+) error {
+	members := map[string]bool{}
+	for i := 0; i < int(network.Spec.Replicas); i++ {
+		members[r.NodeName(network, i)] = true
+	}
+
+	seen := map[string]bool{}
+	for _, group := range fault.Partition {
+		if len(group) == 0 {
+			return fmt.Errorf("empty group in partition")
+		}
+
+		for _, member := range group {
+			if !members[member] {
+				return fmt.Errorf("'%s' is not a member of the network", member)
+			}
+
+			if seen[member] {
+				return fmt.Errorf("'%s' is part of more than one group", member)
+			}
+
+			seen[member] = true
+		}
+	}
+
+	return nil
+}
+
+func (r *MoneroNetworkReconciler) FaultStatus(
+	network *v1alpha1.MoneroNetwork,
+	name string,
+) v1alpha1.MoneroNetworkFaultStatus {
+	for _, status := range network.Status.Faults {
+		if status.Name == name {
+			return *status.DeepCopy()
+		}
+	}
+
+	return v1alpha1.MoneroNetworkFaultStatus{Name: name}
+}
+
+// AssembleFaultNetworkPolicies generates, for each member taking part in a
+// partition, a NetworkPolicy that denies ingress from the members that are
+// in the other groups of the partition, and from them only: pods from the
+// same namespace that aren't part of the other groups, pods from any other
+// namespace, and any address other than those of the pods of the other
+// groups (e.g., external or NodePort traffic) are still allowed in.
+//
+// Denying ingress only applies to new connections, though: most CNIs let
+// the packets of connections that are already established through
+// regardless (as tracked by conntrack), so the members get restarted once
+// the policies are in place (see RestartMembers). With the members
+// `restarting`, or any of their pods without a known address, the
+// addresses of the new pods can't be left out, so no address is allowed in
+// until they're known.
+//
+func (r *MoneroNetworkReconciler) AssembleFaultNetworkPolicies(
+	ctx context.Context,
+	network *v1alpha1.MoneroNetwork,
+	fault v1alpha1.MoneroNetworkFault,
+	restarting bool,
+) ([]*networkingv1.NetworkPolicy, error) {
+	policies := []*networkingv1.NetworkPolicy{}
+
+	for idx, group := range fault.Partition {
+		others := []string{}
+		for otherIdx, otherGroup := range fault.Partition {
+			if otherIdx == idx {
+				continue
+			}
+
+			others = append(others, otherGroup...)
+		}
+
+		ipBlocks := []*networkingv1.IPBlock{}
+
+		if !restarting {
+			var err error
+
+			ipBlocks, err = r.PeerIPBlocks(ctx, network, others)
+			if err != nil {
+				return nil, fmt.Errorf("peer ip blocks: %w", err)
+			}
+		}
+
+		from := []networkingv1.NetworkPolicyPeer{
+			{
+				PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "app",
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   others,
+						},
+					},
+				},
+			},
+			{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      NamespaceNameLabelKey,
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{network.Namespace},
+						},
+					},
+				},
+			},
+		}
+
+		for _, ipBlock := range ipBlocks {
+			from = append(from, networkingv1.NetworkPolicyPeer{
+				IPBlock: ipBlock,
+			})
+		}
+
+		for _, member := range group {
+			policies = append(policies, &networkingv1.NetworkPolicy{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "NetworkPolicy",
+					APIVersion: networkingv1.SchemeGroupVersion.Identifier(),
+				},
+
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      r.FaultNetworkPolicyName(network, fault, member),
+					Namespace: network.Namespace,
+					Labels: map[string]string{
+						NetworkLabelKey: network.Name,
+						FaultLabelKey:   fault.Name,
+					},
+				},
+
+				Spec: networkingv1.NetworkPolicySpec{
+					PodSelector: metav1.LabelSelector{
+						MatchLabels: AppLabel(member),
+					},
+					PolicyTypes: []networkingv1.PolicyType{
+						networkingv1.PolicyTypeIngress,
+					},
+					Ingress: []networkingv1.NetworkPolicyIngressRule{
+						{
+							From: from,
+						},
+					},
+				},
+			})
+		}
+	}
+
+	return policies, nil
+}
+
+// PeerIPBlocks assembles the blocks that allow in any address (IPv4 and
+// IPv6) but those of the pods of `members`.
+//
+// Some CNIs match pod addresses against ipBlocks too, so leaving those of the
+// peers out is what keeps them from being let in regardless of the pod
+// selector. Hence, no blocks are returned at all while any of those pods is
+// missing, has no address yet, or is going away.
+//
+func (r *MoneroNetworkReconciler) PeerIPBlocks(
+	ctx context.Context,
+	network *v1alpha1.MoneroNetwork,
+	members []string,
+) ([]*networkingv1.IPBlock, error) {
+	selector, err := labels.NewRequirement("app", selection.In, members)
+	if err != nil {
+		return nil, fmt.Errorf("new requirement: %w", err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods,
+		client.InNamespace(network.Namespace),
+		client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*selector)},
+	); err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+
+	if len(pods.Items) < len(members)*MemberReplicas(network) {
+		return nil, nil
+	}
+
+	var (
+		v4 = &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}
+		v6 = &networkingv1.IPBlock{CIDR: "::/0"}
+	)
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || len(pod.Status.PodIPs) == 0 {
+			return nil, nil
+		}
+
+		for _, podIP := range pod.Status.PodIPs {
+			ip := net.ParseIP(podIP.IP)
+
+			switch {
+			case ip == nil:
+				continue
+			case ip.To4() != nil:
+				v4.Except = append(v4.Except, ip.String()+"/32")
+			default:
+				v6.Except = append(v6.Except, ip.String()+"/128")
+			}
+		}
+	}
+
+	return []*networkingv1.IPBlock{v4, v6}, nil
+}
+
+// MemberReplicas is the number of replicas (pods) of each member of the
+// network.
+//
+func MemberReplicas(network *v1alpha1.MoneroNetwork) int {
+	if network.Spec.Template.Spec.Replicas == 0 {
+		return 1
+	}
+
+	return int(network.Spec.Template.Spec.Replicas)
+}
+
+// RestartMembers deletes the pods of `members` (to be recreated by their
+// StatefulSets) so that the connections they had established get torn
+// down, with only those allowed by the NetworkPolicies in place being
+// established again.
+//
+func (r *MoneroNetworkReconciler) RestartMembers(
+	ctx context.Context,
+	network *v1alpha1.MoneroNetwork,
+	members []string,
+) error {
+	selector, err := labels.NewRequirement("app", selection.In, members)
+	if err != nil {
+		return fmt.Errorf("new requirement: %w", err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods,
+		client.InNamespace(network.Namespace),
+		client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*selector)},
+	); err != nil {
+		return fmt.Errorf("list pods: %w", err)
+	}
+
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+
+		r.Log.Info("restarting member", "network", network.Name, "pod", pod.Name)
+
+		if err := r.Client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete '%s': %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *MoneroNetworkReconciler) FaultNetworkPolicyName(
+	network *v1alpha1.MoneroNetwork,
+	fault v1alpha1.MoneroNetworkFault,
+	member string,
+) string {
+	return network.Name + "-" + fault.Name + "-" + member
+}
+
+// PruneFaultNetworkPolicies removes the NetworkPolicies created for the
+// network that are not part of the desired set anymore, healing the
+// partitions they implemented.
+//
+func (r *MoneroNetworkReconciler) PruneFaultNetworkPolicies(
+	ctx context.Context,
+	network *v1alpha1.MoneroNetwork,
+	desired []*networkingv1.NetworkPolicy,
+) error {
+	keep := map[string]bool{}
+	for _, policy := range desired {
+		keep[policy.Name] = true
+	}
+
+	existing := &networkingv1.NetworkPolicyList{}
+	if err := r.Client.List(ctx, existing,
+		client.InNamespace(network.Namespace),
+		client.MatchingLabels{NetworkLabelKey: network.Name},
+	); err != nil {
+		return fmt.Errorf("list: %w", err)
+	}
+
+	for idx := range existing.Items {
+		policy := &existing.Items[idx]
+		if keep[policy.Name] {
+			continue
+		}
+
+		if err := r.Client.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete '%s': %w", policy.Name, err)
+		}
+	}
+
+	return nil
+}
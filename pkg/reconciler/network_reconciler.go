@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,27 +36,28 @@ func (r *MoneroNetworkReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return EmptyResult(), fmt.Errorf("get moneronodeset: %w", err)
 	}
 
-	err = r.ReconcileMoneroNetwork(ctx, nodeSet)
+	requeueAfter, err := r.ReconcileMoneroNetwork(ctx, nodeSet)
 	if err != nil {
 		return EmptyResult(), fmt.Errorf("reconcile moneronodeset: %w", err)
 	}
 
-	return EmptyResult(), nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *MoneroNetworkReconciler) ReconcileMoneroNetwork(
 	ctx context.Context,
 	network *v1alpha1.MoneroNetwork,
-) error {
+) (time.Duration, error) {
+	original := network.Status.DeepCopy()
 
 	sets, err := r.AssembleSetOfMoneroNodeSets(network)
 	if err != nil {
-		return fmt.Errorf("assemble set of moneronodesets: %w", err)
+		return 0, fmt.Errorf("assemble set of moneronodesets: %w", err)
 	}
 
 	for _, set := range sets {
 		if err := r.Apply(ctx, set); err != nil {
-			return fmt.Errorf("apply: %w", err)
+			return 0, fmt.Errorf("apply: %w", err)
 		}
 	}
 
@@ -62,7 +66,30 @@ func (r *MoneroNetworkReconciler) ReconcileMoneroNetwork(
 	// diff
 	// reach
 
-	return nil
+	requeueAfter, err := r.ReconcileFaults(ctx, network)
+	if err != nil {
+		return 0, fmt.Errorf("reconcile faults: %w", err)
+	}
+
+	meta.SetStatusCondition(&network.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "Succeeded",
+		Message: "objects successfully applied",
+	})
+
+	// writing an unchanged status would only bump the resource version
+	// for nothing.
+	//
+	if equality.Semantic.DeepEqual(original, &network.Status) {
+		return requeueAfter, nil
+	}
+
+	if err := r.Client.Status().Update(ctx, network); err != nil {
+		return 0, fmt.Errorf("status update: %w", err)
+	}
+
+	return requeueAfter, nil
 }
 
 func (r *MoneroNetworkReconciler) AssembleSetOfMoneroNodeSets(
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/cirocosta/go-monero/pkg/daemonrpc"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -17,6 +20,13 @@ import (
 	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
 )
 
+const (
+	// NodeSetStatusInterval is how often monerod gets queried for its
+	// synchronization status.
+	//
+	NodeSetStatusInterval = 30 * time.Second
+)
+
 type MoneroNodeSetReconciler struct {
 	Log    logr.Logger
 	Client client.Client
@@ -39,13 +49,28 @@ func (r *MoneroNodeSetReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return EmptyResult(), fmt.Errorf("reconcile moneronodeset: %w", err)
 	}
 
-	return EmptyResult(), nil
+	return ctrl.Result{RequeueAfter: NodeSetStatusInterval}, nil
 }
 
 func (r *MoneroNodeSetReconciler) ReconcileMoneroNodeSet(
 	ctx context.Context,
 	nodeSet *v1alpha1.MoneroNodeSet,
 ) error {
+	if nodeSet.Spec.Tor.Enabled && nodeSet.Spec.Tor.SecretRef.Name != "" {
+		valid, err := r.ResolveTorSecretRef(ctx, nodeSet)
+		if err != nil {
+			return fmt.Errorf("resolve tor secret: %w", err)
+		}
+
+		if !valid {
+			if err := r.Client.Status().Update(ctx, nodeSet); err != nil {
+				return fmt.Errorf("status update: %w", err)
+			}
+
+			return nil
+		}
+	}
+
 	objs, err := r.GenerateObjects(ctx, nodeSet)
 	if err != nil {
 		return fmt.Errorf("setup objs: %w", err)
@@ -55,14 +80,20 @@ func (r *MoneroNodeSetReconciler) ReconcileMoneroNodeSet(
 		return fmt.Errorf("apply objects: %w", err)
 	}
 
-	nodeSet.Status.Conditions = []metav1.Condition{
-		{
-			Type:               "Ready",
-			Status:             metav1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
-			Reason:             "Succeeded",
-			Message:            "objects successfully applied",
-		},
+	if err := r.PruneTorObjects(ctx, nodeSet); err != nil {
+		return fmt.Errorf("prune tor objects: %w", err)
+	}
+
+	meta.SetStatusCondition(&nodeSet.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "Succeeded",
+		Message: "objects successfully applied",
+	})
+
+	if err := r.ObserveSync(ctx, nodeSet); err != nil {
+		r.Log.Info("failed to observe sync status", "nodeset", nodeSet.Name, "err", err.Error())
+		nodeSet.Status.Synchronized = false
 	}
 
 	if err := r.Client.Status().Update(ctx, nodeSet); err != nil {
@@ -78,28 +109,34 @@ func (r *MoneroNodeSetReconciler) GenerateObjects(
 ) ([]client.Object, error) {
 	objs := []client.Object{}
 
-	if nodeSet.Spec.Tor.Enabled {
-		hiddenServiceSecret := NewTorHiddenServiceSecret(nodeSet)
-		torSecretsRec := &TorSecretsReconciler{}
+	if err := r.ResolvePeers(ctx, nodeSet); err != nil {
+		return nil, fmt.Errorf("resolve peers: %w", err)
+	}
+
+	if err := r.ResolveUnrestrictedRPC(ctx, nodeSet); err != nil {
+		return nil, fmt.Errorf("resolve unrestricted rpc: %w", err)
+	}
 
-		if err := torSecretsRec.FillSecret(hiddenServiceSecret); err != nil {
-			return nil, fmt.Errorf("fill secret: %w", err)
+	if nodeSet.Spec.Tor.Enabled {
+		torObjs, err := r.GenerateTorObjects(ctx, nodeSet)
+		if err != nil {
+			return nil, fmt.Errorf("generate tor objects: %w", err)
 		}
 
-		hostname, found := hiddenServiceSecret.Data["hostname"]
-		if !found {
-			return nil, fmt.Errorf("tor hidden service secret '%s' should be filled but isn't - didn't find hostname",
-				hiddenServiceSecret.GetName(),
-			)
+		objs = append(objs, torObjs...)
+	} else {
+		nodeSet.Status.Tor = nil
+	}
+
+	if nodeSet.Spec.Monerod.UnrestrictedRPC.Enabled {
+		credentials := NewCredentialsSecret(RPCCredentialsSecretName(nodeSet), nodeSet.Namespace)
+		if err := FillCredentialsSecret(ctx, r.Client, credentials); err != nil {
+			return nil, fmt.Errorf("fill credentials secret: %w", err)
 		}
-		nodeSet.Status.Tor.Address = string(hostname)
 
 		objs = append(objs,
-			NewTorHiddenServiceService(nodeSet),
-			NewTorHiddenServiceDeployment(nodeSet),
-			NewTorProxyConfigMap(nodeSet),
-			NewTorHiddenServiceConfigMap(nodeSet),
-			hiddenServiceSecret,
+			credentials,
+			NewRPCService(nodeSet),
 		)
 	}
 
@@ -111,6 +148,132 @@ func (r *MoneroNodeSetReconciler) GenerateObjects(
 	return objs, nil
 }
 
+// ResolvePeers turns the peers declared in the spec into the corresponding
+// monerod flags, merging them into the set of extra arguments.
+//
+func (r *MoneroNodeSetReconciler) ResolvePeers(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) error {
+	args := []string{}
+
+	for _, peer := range nodeSet.Spec.Peers {
+		address, err := r.PeerAddress(ctx, nodeSet, peer)
+		if err != nil {
+			return fmt.Errorf("peer address: %w", err)
+		}
+
+		flag, err := PeerFlag(peer.Mode)
+		if err != nil {
+			return fmt.Errorf("peer flag: %w", err)
+		}
+
+		args = append(args, flag+"="+address)
+	}
+
+	nodeSet.Spec.Monerod.Args = MergedSlice(nodeSet.Spec.Monerod.Args, args)
+	return nil
+}
+
+// PeerAddress resolves a peer to the `host:port` address that monerod should
+// connect to.
+//
+// Without a client (e.g., during a dry-run), references to other node sets
+// are resolved purely from their names.
+//
+func (r *MoneroNodeSetReconciler) PeerAddress(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+	peer v1alpha1.MoneroNodeSetPeer,
+) (string, error) {
+	if peer.NodeSetRef == nil {
+		if peer.Address == "" {
+			return "", fmt.Errorf("peer must specify either nodeSetRef or address")
+		}
+
+		return peer.Address, nil
+	}
+
+	namespace := peer.NodeSetRef.Namespace
+	if namespace == "" {
+		namespace = nodeSet.Namespace
+	}
+
+	referenced := &v1alpha1.MoneroNodeSet{}
+	referenced.Name = peer.NodeSetRef.Name
+	referenced.Namespace = namespace
+	referenced.Spec.Monerod.Network = nodeSet.Spec.Monerod.Network
+
+	if r.Client != nil {
+		var err error
+
+		referenced, err = r.GetMoneroNodeSet(ctx, peer.NodeSetRef.Name, namespace)
+		if err != nil {
+			return "", fmt.Errorf("get referenced moneronodeset: %w", err)
+		}
+	}
+
+	return fmt.Sprintf("%s.%s:%d",
+		MoneroServiceName(referenced), referenced.Namespace, Ports(referenced).P2P,
+	), nil
+}
+
+// IsSoloMiningAgainst tells whether a mining set solo mines against a
+// particular node set.
+//
+func IsSoloMiningAgainst(
+	miningSet *v1alpha1.MoneroMiningNodeSet,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) bool {
+	if miningSet.Spec.Solo == nil {
+		return false
+	}
+
+	namespace := miningSet.Spec.Solo.NodeSetRef.Namespace
+	if namespace == "" {
+		namespace = miningSet.Namespace
+	}
+
+	return miningSet.Spec.Solo.NodeSetRef.Name == nodeSet.Name && namespace == nodeSet.Namespace
+}
+
+// ObserveSync queries monerod (through the restricted RPC interface) for its
+// synchronization status, recording it in the node set's status.
+//
+func (r *MoneroNodeSetReconciler) ObserveSync(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) error {
+	rpc, err := daemonrpc.NewClient("http://" + DaemonAddress(nodeSet, false))
+	if err != nil {
+		return fmt.Errorf("new client: %w", err)
+	}
+
+	info, err := rpc.GetInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("get info: %w", err)
+	}
+
+	nodeSet.Status.Synchronized = info.Synchronized
+	nodeSet.Status.Height = uint64(info.Height)
+	nodeSet.Status.TargetHeight = uint64(info.TargetHeight)
+
+	return nil
+}
+
+func PeerFlag(mode string) (string, error) {
+	switch mode {
+	case v1alpha1.PeerModeExclusive:
+		return "--add-exclusive-node", nil
+	case v1alpha1.PeerModePriority, "":
+		return "--add-priority-node", nil
+	case v1alpha1.PeerModeSeed:
+		return "--seed-node", nil
+	}
+
+	return "", fmt.Errorf("unknown peer mode '%s'", mode)
+}
+
 func (r *MoneroNodeSetReconciler) ApplyObjects(
 	ctx context.Context,
 	nodeSet *v1alpha1.MoneroNodeSet,
@@ -0,0 +1,199 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+)
+
+const (
+	UnrestrictedRPCConditionType = "UnrestrictedRPC"
+
+	// UnrestrictedRPCDisabledReason is the reason given when what depends
+	// on the unrestricted RPC interface of a node set can't make use of it
+	// because it's not enabled.
+	//
+	UnrestrictedRPCDisabledReason = "UnrestrictedRPCDisabled"
+
+	// UnrestrictedRPCLoginRequiredReason is the reason given when the
+	// node set requires a login for its unrestricted RPC interface, which
+	// what depends on it can't provide.
+	//
+	UnrestrictedRPCLoginRequiredReason = "LoginRequired"
+)
+
+// UnrestrictedRPC is the effective configuration of the unrestricted RPC
+// interface of a node set, once what depends on it is accounted for.
+//
+// Solo miners (xmrig) and p2pool need the unrestricted RPC interface for
+// getting block templates, but neither can authenticate against monerod.
+// As that interface also allows for administering the node (e.g.,
+// `stop_daemon` or `set_bans`), it's never enabled (nor is its login
+// dropped) on their behalf: that's left to the spec, with the node set and
+// its dependents reporting what's missing until then.
+//
+type UnrestrictedRPC struct {
+	v1alpha1.MonerodUnrestrictedRPCConfig
+
+	// ZMQ tells whether the ZMQ publisher should be enabled, as p2pool
+	// relies on it to be notified of new blocks and transactions.
+	//
+	ZMQ bool
+
+	// Dependents lists (as `<kind>/<name>`) what relies on the
+	// unrestricted RPC interface without being able to log in.
+	//
+	Dependents []string
+}
+
+// Usable tells whether the unrestricted RPC interface can be used by solo
+// miners and p2pool: enabled, and without requiring credentials.
+//
+func (u UnrestrictedRPC) Usable() bool {
+	return u.Enabled && u.DisableLogin
+}
+
+// Condition is the condition reporting on whether the dependents can make
+// use of the unrestricted RPC interface, if there are any.
+//
+func (u UnrestrictedRPC) Condition() (metav1.Condition, bool) {
+	if len(u.Dependents) == 0 {
+		return metav1.Condition{}, false
+	}
+
+	dependents := strings.Join(u.Dependents, ", ")
+
+	switch {
+	case !u.Enabled:
+		return metav1.Condition{
+			Type:    UnrestrictedRPCConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  UnrestrictedRPCDisabledReason,
+			Message: "not enabled, but required by " + dependents,
+		}, true
+	case !u.DisableLogin:
+		return metav1.Condition{
+			Type:    UnrestrictedRPCConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  UnrestrictedRPCLoginRequiredReason,
+			Message: "login required, which can't be used by " + dependents,
+		}, true
+	}
+
+	return metav1.Condition{
+		Type:    UnrestrictedRPCConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Configured",
+		Message: "enabled without login, used by " + dependents,
+	}, true
+}
+
+// UnusableCondition is the (false) condition of type `conditionType` that a
+// dependent of the node set reports when it can't make use of the
+// unrestricted RPC interface.
+//
+func (u UnrestrictedRPC) UnusableCondition(
+	conditionType string,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) metav1.Condition {
+	condition := metav1.Condition{
+		Type:   conditionType,
+		Status: metav1.ConditionFalse,
+		Reason: UnrestrictedRPCDisabledReason,
+		Message: fmt.Sprintf("moneronodeset '%s' must have its unrestricted rpc interface enabled "+
+			"(set unrestrictedRPC.enabled and unrestrictedRPC.disableLogin)", nodeSet.Name),
+	}
+
+	if u.Enabled {
+		condition.Reason = UnrestrictedRPCLoginRequiredReason
+		condition.Message = fmt.Sprintf("moneronodeset '%s' requires a login for its unrestricted rpc interface "+
+			"(set unrestrictedRPC.disableLogin)", nodeSet.Name)
+	}
+
+	return condition
+}
+
+// EffectiveUnrestrictedRPC figures out how the unrestricted RPC interface of
+// a node set ends up configured, taking into account the
+// MoneroMiningNodeSets that solo mine against it and the MoneroP2Pools
+// backed by it.
+//
+// Only the ZMQ publisher (which merely announces blocks and transactions)
+// gets enabled for them: the unrestricted RPC interface is exactly as in the
+// spec.
+//
+// Without a client (e.g., during a dry-run), it's exactly what's in the spec.
+//
+func EffectiveUnrestrictedRPC(
+	ctx context.Context,
+	c client.Client,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) (UnrestrictedRPC, error) {
+	rpc := UnrestrictedRPC{
+		MonerodUnrestrictedRPCConfig: nodeSet.Spec.Monerod.UnrestrictedRPC,
+		ZMQ:                          nodeSet.Spec.Monerod.ZMQ.Enabled,
+	}
+
+	if c == nil {
+		return rpc, nil
+	}
+
+	miningSets := &v1alpha1.MoneroMiningNodeSetList{}
+	if err := c.List(ctx, miningSets); err != nil {
+		return rpc, fmt.Errorf("list monerominingnodesets: %w", err)
+	}
+
+	for _, miningSet := range miningSets.Items {
+		if IsSoloMiningAgainst(&miningSet, nodeSet) {
+			rpc.Dependents = append(rpc.Dependents,
+				fmt.Sprintf("monerominingnodeset/%s/%s", miningSet.Namespace, miningSet.Name),
+			)
+		}
+	}
+
+	pools := &v1alpha1.MoneroP2PoolList{}
+	if err := c.List(ctx, pools, client.InNamespace(nodeSet.Namespace)); err != nil {
+		return rpc, fmt.Errorf("list monerop2pools: %w", err)
+	}
+
+	for _, pool := range pools.Items {
+		if pool.Spec.NodeSetRef.Name == nodeSet.Name {
+			rpc.Dependents = append(rpc.Dependents, "monerop2pool/"+pool.Name)
+			rpc.ZMQ = true
+		}
+	}
+
+	return rpc, nil
+}
+
+// ResolveUnrestrictedRPC enables the node set's ZMQ publisher for what
+// depends on it, reporting through the UnrestrictedRPC condition whether the
+// dependents can make use of the unrestricted RPC interface.
+//
+func (r *MoneroNodeSetReconciler) ResolveUnrestrictedRPC(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) error {
+	rpc, err := EffectiveUnrestrictedRPC(ctx, r.Client, nodeSet)
+	if err != nil {
+		return fmt.Errorf("effective unrestricted rpc: %w", err)
+	}
+
+	nodeSet.Spec.Monerod.UnrestrictedRPC = rpc.MonerodUnrestrictedRPCConfig
+	nodeSet.Spec.Monerod.ZMQ.Enabled = rpc.ZMQ
+
+	condition, found := rpc.Condition()
+	if !found {
+		meta.RemoveStatusCondition(&nodeSet.Status.Conditions, UnrestrictedRPCConditionType)
+		return nil
+	}
+
+	meta.SetStatusCondition(&nodeSet.Status.Conditions, condition)
+	return nil
+}
@@ -0,0 +1,307 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+	"github.com/cirocosta/monero-operator/pkg/tor"
+)
+
+const (
+	TorSecretNotFoundReason = "TorSecretNotFound"
+	TorSecretInvalidReason  = "TorSecretInvalid"
+)
+
+// ResolveTorSecretRef validates the Secret referenced by `tor.secretRef` as
+// the identity of the hidden service of the first replica, reporting its
+// address in the status.
+//
+// The Secret is only ever read, never modified. If it's missing or
+// malformed, the Ready condition is set to false with the reason, and false
+// is returned so that nothing gets applied until it's fixed.
+//
+func (r *MoneroNodeSetReconciler) ResolveTorSecretRef(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) (bool, error) {
+	name := nodeSet.Spec.Tor.SecretRef.Name
+
+	condition := metav1.Condition{
+		Type:   "Ready",
+		Status: metav1.ConditionFalse,
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: nodeSet.Namespace,
+	}, secret); err != nil {
+		if !errors.IsNotFound(err) {
+			return false, fmt.Errorf("get secret '%s': %w", name, err)
+		}
+
+		condition.Reason = TorSecretNotFoundReason
+		condition.Message = fmt.Sprintf("tor secret '%s' not found", name)
+		meta.SetStatusCondition(&nodeSet.Status.Conditions, condition)
+
+		return false, nil
+	}
+
+	address, err := tor.Credentials(secret.Data).OnionAddress()
+	if err != nil {
+		condition.Reason = TorSecretInvalidReason
+		condition.Message = fmt.Sprintf("tor secret '%s': %v", name, err)
+		meta.SetStatusCondition(&nodeSet.Status.Conditions, condition)
+
+		return false, nil
+	}
+
+	SetTorAddress(nodeSet, 0, address)
+	return true, nil
+}
+
+// SetTorAddress records in the status the onion address of the replica at
+// index `idx`.
+//
+func SetTorAddress(nodeSet *v1alpha1.MoneroNodeSet, idx int, address string) {
+	for i := range nodeSet.Status.Tor {
+		if int(nodeSet.Status.Tor[i].Replica) == idx {
+			nodeSet.Status.Tor[i].Address = address
+			return
+		}
+	}
+
+	nodeSet.Status.Tor = append(nodeSet.Status.Tor, v1alpha1.MoneroNodeStatusTor{
+		Replica: uint32(idx),
+		Address: address,
+	})
+}
+
+// GenerateTorObjects assembles the objects that make up the hidden services
+// of the node set, one per replica (each with its own identity), recording
+// their addresses in the status.
+//
+func (r *MoneroNodeSetReconciler) GenerateTorObjects(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) ([]client.Object, error) {
+	objs := []client.Object{
+		NewTorProxyConfigMap(nodeSet),
+	}
+
+	statuses := []v1alpha1.MoneroNodeStatusTor{}
+
+	for idx := 0; idx < int(nodeSet.Spec.Replicas); idx++ {
+		objs = append(objs,
+			NewTorHiddenServiceService(nodeSet, idx),
+			NewTorHiddenServiceDeployment(nodeSet, idx),
+			NewTorHiddenServiceConfigMap(nodeSet, idx),
+		)
+
+		// a referenced secret is only ever read (see
+		// ResolveTorSecretRef), never applied.
+		//
+		if idx == 0 && nodeSet.Spec.Tor.SecretRef.Name != "" {
+			statuses = append(statuses, v1alpha1.MoneroNodeStatusTor{
+				Replica: 0,
+				Address: TorAddress(nodeSet, 0),
+			})
+
+			continue
+		}
+
+		secret, err := r.TorHiddenServiceSecret(ctx, nodeSet, idx)
+		if err != nil {
+			return nil, fmt.Errorf("tor hidden service secret %d: %w", idx, err)
+		}
+
+		statuses = append(statuses, v1alpha1.MoneroNodeStatusTor{
+			Replica: uint32(idx),
+			Address: tor.Credentials(secret.Data).Hostname(),
+		})
+
+		objs = append(objs, secret)
+	}
+
+	nodeSet.Status.Tor = statuses
+
+	// only now that all addresses are known can monerod be told which
+	// one to announce in each replica.
+	//
+	objs = append(objs, NewMonerodConfigMap(nodeSet))
+
+	return objs, nil
+}
+
+// TorHiddenServiceSecret assembles the Secret holding the identity of the
+// hidden service of the replica at index `idx` generated by the operator,
+// reusing the existing one so that the address stays the same across
+// reconciliations.
+//
+// For the first replica, the identity of the single hidden service that
+// earlier versions of the operator ran for the whole node set is carried
+// over, so that the address that was already known keeps on working.
+//
+// Without a client (e.g., during a dry-run), a new identity is generated.
+//
+func (r *MoneroNodeSetReconciler) TorHiddenServiceSecret(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+	idx int,
+) (*corev1.Secret, error) {
+	secret := NewTorHiddenServiceSecret(nodeSet, idx)
+
+	if r.Client != nil {
+		candidates := []string{secret.Name}
+		if idx == 0 {
+			candidates = append(candidates, LegacyTorHiddenServiceSecretName(nodeSet))
+		}
+
+		for _, name := range candidates {
+			existing := &corev1.Secret{}
+			if err := r.Client.Get(ctx, client.ObjectKey{
+				Name:      name,
+				Namespace: secret.Namespace,
+			}, existing); client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("get secret '%s': %w", name, err)
+			}
+
+			if (&TorSecretsReconciler{}).SecretAlreadyFilled(existing) {
+				secret.Data = existing.Data
+				return secret, nil
+			}
+		}
+	}
+
+	torSecretsRec := &TorSecretsReconciler{}
+	if err := torSecretsRec.FillSecret(secret); err != nil {
+		return nil, fmt.Errorf("fill secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// NodeSetReferencesSecret tells whether the node set makes use of the
+// Secret named `name` as the identity of its hidden service.
+//
+func NodeSetReferencesSecret(nodeSet *v1alpha1.MoneroNodeSet, name string) bool {
+	return nodeSet.Spec.Tor.Enabled && nodeSet.Spec.Tor.SecretRef.Name == name
+}
+
+// LegacyTorHiddenServiceSecretName is the name of the Secret that earlier
+// versions of the operator kept the identity of the node set's single
+// hidden service in.
+//
+func LegacyTorHiddenServiceSecretName(nodeSet *v1alpha1.MoneroNodeSet) string {
+	return nodeSet.Name + "-" + "tor"
+}
+
+// PruneTorObjects removes the hidden services that are no longer needed:
+// those of replicas that got scaled away (or all of them, with tor
+// disabled), as well as the single one that earlier versions of the
+// operator ran for the whole node set.
+//
+// Only objects controlled by the node set are ever removed, so that
+// a Secret referenced through `tor.secretRef` is left alone.
+//
+func (r *MoneroNodeSetReconciler) PruneTorObjects(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) error {
+	lists := []client.ObjectList{
+		&appsv1.DeploymentList{},
+		&corev1.ServiceList{},
+		&corev1.ConfigMapList{},
+		&corev1.SecretList{},
+	}
+
+	for _, list := range lists {
+		if err := r.Client.List(ctx, list,
+			client.InNamespace(nodeSet.Namespace),
+			client.MatchingLabels{NodeSetLabelKey: nodeSet.Name},
+			client.HasLabels{TorReplicaLabelKey},
+		); err != nil {
+			return fmt.Errorf("list: %w", err)
+		}
+
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return fmt.Errorf("extract list: %w", err)
+		}
+
+		for _, o := range objs {
+			obj := o.(client.Object)
+
+			idx, err := strconv.Atoi(obj.GetLabels()[TorReplicaLabelKey])
+			if err == nil && nodeSet.Spec.Tor.Enabled && idx < int(nodeSet.Spec.Replicas) {
+				continue
+			}
+
+			if err := r.DeleteControlled(ctx, nodeSet, obj); err != nil {
+				return fmt.Errorf("delete controlled: %w", err)
+			}
+		}
+	}
+
+	legacy := []client.Object{
+		&appsv1.Deployment{},
+		&corev1.Service{},
+		&corev1.ConfigMap{},
+		&corev1.Secret{},
+	}
+
+	names := []string{
+		nodeSet.Name + "-tor-proxy",
+		nodeSet.Name + "-tor-hidden-service",
+		nodeSet.Name + "-tor-hidden-service",
+		LegacyTorHiddenServiceSecretName(nodeSet),
+	}
+
+	for i, obj := range legacy {
+		if err := r.Client.Get(ctx, client.ObjectKey{
+			Name:      names[i],
+			Namespace: nodeSet.Namespace,
+		}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return fmt.Errorf("get '%s': %w", names[i], err)
+		}
+
+		if err := r.DeleteControlled(ctx, nodeSet, obj); err != nil {
+			return fmt.Errorf("delete controlled: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteControlled deletes `obj` as long as it's controlled by the node set.
+//
+func (r *MoneroNodeSetReconciler) DeleteControlled(
+	ctx context.Context,
+	nodeSet *v1alpha1.MoneroNodeSet,
+	obj client.Object,
+) error {
+	if !metav1.IsControlledBy(obj, nodeSet) {
+		return nil
+	}
+
+	r.Log.Info("deleting tor object", "nodeset", nodeSet.Name, "name", obj.GetName())
+
+	if err := r.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete '%s': %w", obj.GetName(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,487 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+	"github.com/cirocosta/monero-operator/pkg/p2pool"
+)
+
+const (
+	// P2PoolStatusInterval is how often the sidechain statistics of a
+	// p2pool instance get refreshed.
+	//
+	P2PoolStatusInterval = 30 * time.Second
+)
+
+type MoneroP2PoolReconciler struct {
+	Log    logr.Logger
+	Client client.Client
+}
+
+func (r *MoneroP2PoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pool, err := r.GetMoneroP2Pool(ctx, req.Name, req.Namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return EmptyResult(), nil
+		}
+
+		return EmptyResult(), fmt.Errorf("get monerop2pool: %w", err)
+	}
+
+	pool.ApplyDefaults()
+
+	err = r.ReconcileMoneroP2Pool(ctx, pool)
+	if err != nil {
+		return EmptyResult(), fmt.Errorf("reconcile monerop2pool: %w", err)
+	}
+
+	return ctrl.Result{RequeueAfter: P2PoolStatusInterval}, nil
+}
+
+func (r *MoneroP2PoolReconciler) ReconcileMoneroP2Pool(
+	ctx context.Context,
+	pool *v1alpha1.MoneroP2Pool,
+) error {
+	objs, condition, err := r.GenerateObjects(ctx, pool)
+	if err != nil {
+		return fmt.Errorf("generate objects: %w", err)
+	}
+
+	if condition != nil {
+		meta.SetStatusCondition(&pool.Status.Conditions, *condition)
+
+		if err := r.Client.Status().Update(ctx, pool); err != nil {
+			return fmt.Errorf("status update: %w", err)
+		}
+
+		return nil
+	}
+
+	for _, o := range objs {
+		r.SetOwnerRef(pool, o)
+
+		if err := r.Apply(ctx, o); err != nil {
+			return fmt.Errorf("apply '%s %s': %w",
+				o.GetObjectKind().GroupVersionKind().String(),
+				o.GetName(),
+				err,
+			)
+		}
+	}
+
+	meta.SetStatusCondition(&pool.Status.Conditions, r.ReadyCondition(ctx, pool))
+
+	if err := r.Client.Status().Update(ctx, pool); err != nil {
+		return fmt.Errorf("status update: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateObjects assembles the objects that make up the pool or, if the
+// node set can't back it, a not ready condition saying why.
+//
+func (r *MoneroP2PoolReconciler) GenerateObjects(
+	ctx context.Context,
+	pool *v1alpha1.MoneroP2Pool,
+) ([]client.Object, *metav1.Condition, error) {
+	if pool.Spec.NodeSetRef.Namespace != "" && pool.Spec.NodeSetRef.Namespace != pool.Namespace {
+		return nil, nil, fmt.Errorf("moneronodeset must be in namespace '%s'", pool.Namespace)
+	}
+
+	if pool.Spec.Address == "" && pool.Spec.AddressSecretRef == nil {
+		return nil, nil, fmt.Errorf("either address or addressSecretRef must be specified")
+	}
+
+	nodeSet := &v1alpha1.MoneroNodeSet{}
+	nodeSet.Name = pool.Spec.NodeSetRef.Name
+	nodeSet.Namespace = pool.Namespace
+
+	if r.Client != nil {
+		if err := r.Client.Get(ctx, client.ObjectKey{
+			Name:      nodeSet.Name,
+			Namespace: nodeSet.Namespace,
+		}, nodeSet); err != nil {
+			return nil, nil, fmt.Errorf("get moneronodeset %s/%s: %w", nodeSet.Namespace, nodeSet.Name, err)
+		}
+	}
+
+	nodeSet.ApplyDefaults()
+
+	rpc, err := EffectiveUnrestrictedRPC(ctx, r.Client, nodeSet)
+	if err != nil {
+		return nil, nil, fmt.Errorf("effective unrestricted rpc: %w", err)
+	}
+
+	if !rpc.Usable() {
+		condition := rpc.UnusableCondition("Ready", nodeSet)
+		return nil, &condition, nil
+	}
+
+	return []client.Object{
+		NewP2PoolService(pool),
+		NewP2PoolDeployment(pool, nodeSet),
+	}, nil, nil
+}
+
+// ReadyCondition checks whether p2pool is reporting statistics through its
+// data API, filling the status with them.
+//
+func (r *MoneroP2PoolReconciler) ReadyCondition(
+	ctx context.Context,
+	pool *v1alpha1.MoneroP2Pool,
+) metav1.Condition {
+	condition := metav1.Condition{
+		Type:   "Ready",
+		Status: metav1.ConditionFalse,
+	}
+
+	c := p2pool.NewClient(P2PoolAPIAddress(pool))
+
+	stats, err := c.PoolStats(ctx)
+	if err != nil {
+		condition.Reason = "Unreachable"
+		condition.Message = err.Error()
+		return condition
+	}
+
+	local, err := c.LocalStratum(ctx)
+	if err != nil {
+		condition.Reason = "Unreachable"
+		condition.Message = err.Error()
+		return condition
+	}
+
+	pool.Status.SidechainHeight = stats.PoolStatistics.SidechainHeight
+	pool.Status.PoolHashrate = stats.PoolStatistics.HashRate
+	pool.Status.PoolMiners = stats.PoolStatistics.Miners
+	pool.Status.LocalHashrate = local.Hashrate15m
+	pool.Status.SharesFound = local.SharesFound
+	pool.Status.SharesFailed = local.SharesFailed
+	pool.Status.Connections = local.Connections
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "Succeeded"
+	condition.Message = "p2pool reporting statistics"
+
+	return condition
+}
+
+func P2PoolServiceName(pool *v1alpha1.MoneroP2Pool) string {
+	return pool.Name
+}
+
+// P2PoolStratumAddress is the `host:port` address that miners should point
+// at.
+//
+func P2PoolStratumAddress(pool *v1alpha1.MoneroP2Pool) string {
+	return fmt.Sprintf("%s.%s:%d",
+		P2PoolServiceName(pool), pool.Namespace, P2PoolStratumPortNumber,
+	)
+}
+
+func P2PoolAPIAddress(pool *v1alpha1.MoneroP2Pool) string {
+	return fmt.Sprintf("http://%s.%s:%d",
+		P2PoolServiceName(pool), pool.Namespace, P2PoolAPIPortNumber,
+	)
+}
+
+func P2PoolP2PPort(pool *v1alpha1.MoneroP2Pool) uint16 {
+	if pool.Spec.Mini {
+		return P2PoolMiniP2PPortNumber
+	}
+
+	return P2PoolP2PPortNumber
+}
+
+func NewP2PoolContainer(
+	pool *v1alpha1.MoneroP2Pool,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) corev1.Container {
+	ports := Ports(nodeSet)
+
+	defaultArgs := []string{
+		"--host=" + RPCServiceName(nodeSet),
+		fmt.Sprintf("--rpc-port=%d", ports.RPC),
+		fmt.Sprintf("--zmq-port=%d", ports.ZMQPub),
+		"--wallet=$(" + P2PoolAddressEnvName + ")",
+
+		fmt.Sprintf("--stratum=0.0.0.0:%d", P2PoolStratumPortNumber),
+		fmt.Sprintf("--p2p=0.0.0.0:%d", P2PoolP2PPort(pool)),
+
+		"--data-api=" + P2PoolDataVolumeMountPath,
+		"--local-api",
+	}
+
+	if pool.Spec.Mini {
+		defaultArgs = append(defaultArgs, "--mini")
+	}
+
+	env := corev1.EnvVar{
+		Name:  P2PoolAddressEnvName,
+		Value: pool.Spec.Address,
+	}
+
+	if pool.Spec.AddressSecretRef != nil {
+		env.Value = ""
+		env.ValueFrom = &corev1.EnvVarSource{
+			SecretKeyRef: pool.Spec.AddressSecretRef,
+		}
+	}
+
+	command := append([]string{
+		"p2pool",
+	}, MergedSlice(defaultArgs, pool.Spec.Args)...)
+
+	return corev1.Container{
+		Name:    P2PoolContainerName,
+		Image:   pool.Spec.Image,
+		Command: command,
+		Env:     []corev1.EnvVar{env},
+		ReadinessProbe: &corev1.Probe{
+			PeriodSeconds:       15,
+			InitialDelaySeconds: 5,
+			FailureThreshold:    5,
+			Handler: corev1.Handler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromString(P2PoolStratumPortName),
+				},
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          P2PoolStratumPortName,
+				ContainerPort: int32(P2PoolStratumPortNumber),
+				Protocol:      corev1.ProtocolTCP,
+			},
+			{
+				Name:          P2PoolP2PPortName,
+				ContainerPort: int32(P2PoolP2PPort(pool)),
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      P2PoolDataVolumeName,
+				MountPath: P2PoolDataVolumeMountPath,
+			},
+		},
+	}
+}
+
+// NewP2PoolAPIContainer serves the files that p2pool writes to its data API
+// directory over HTTP so that the operator can gather statistics from it.
+//
+func NewP2PoolAPIContainer() corev1.Container {
+	return corev1.Container{
+		Name:  P2PoolAPIContainerName,
+		Image: P2PoolAPIImage,
+		Command: []string{
+			"httpd", "-f",
+			"-p", fmt.Sprintf("%d", P2PoolAPIPortNumber),
+			"-h", P2PoolDataVolumeMountPath,
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          P2PoolAPIPortName,
+				ContainerPort: int32(P2PoolAPIPortNumber),
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      P2PoolDataVolumeName,
+				MountPath: P2PoolDataVolumeMountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+func NewP2PoolDeployment(
+	pool *v1alpha1.MoneroP2Pool,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) *appsv1.Deployment {
+	obj := &appsv1.Deployment{}
+
+	obj.TypeMeta = metav1.TypeMeta{
+		Kind:       "Deployment",
+		APIVersion: appsv1.SchemeGroupVersion.Identifier(),
+	}
+
+	obj.ObjectMeta = metav1.ObjectMeta{
+		Name:      pool.Name,
+		Namespace: pool.Namespace,
+	}
+
+	obj.Spec = appsv1.DeploymentSpec{
+		Replicas:             pointer.Int32Ptr(1),
+		RevisionHistoryLimit: pointer.Int32Ptr(0),
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RecreateDeploymentStrategyType,
+		},
+		Selector: &metav1.LabelSelector{
+			MatchLabels: AppLabel(pool.Name),
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: AppLabel(pool.Name),
+			},
+			Spec: corev1.PodSpec{
+				TerminationGracePeriodSeconds: pointer.Int64Ptr(60),
+				Containers: []corev1.Container{
+					NewP2PoolContainer(pool, nodeSet),
+					NewP2PoolAPIContainer(),
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: P2PoolDataVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							EmptyDir: &corev1.EmptyDirVolumeSource{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return obj
+}
+
+func NewP2PoolService(pool *v1alpha1.MoneroP2Pool) *corev1.Service {
+	obj := &corev1.Service{}
+
+	obj.TypeMeta = metav1.TypeMeta{
+		Kind:       "Service",
+		APIVersion: corev1.SchemeGroupVersion.Identifier(),
+	}
+
+	l := AppLabel(pool.Name)
+
+	obj.ObjectMeta = metav1.ObjectMeta{
+		Name:      P2PoolServiceName(pool),
+		Namespace: pool.Namespace,
+		Labels:    l,
+	}
+
+	obj.Spec = corev1.ServiceSpec{
+		Selector: l,
+		Ports: []corev1.ServicePort{
+			{
+				Name:       P2PoolStratumPortName,
+				Port:       int32(P2PoolStratumPortNumber),
+				TargetPort: intstr.FromInt(int(P2PoolStratumPortNumber)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+			{
+				Name:       P2PoolP2PPortName,
+				Port:       int32(P2PoolP2PPort(pool)),
+				TargetPort: intstr.FromInt(int(P2PoolP2PPort(pool))),
+				Protocol:   corev1.ProtocolTCP,
+			},
+			{
+				Name:       P2PoolAPIPortName,
+				Port:       int32(P2PoolAPIPortNumber),
+				TargetPort: intstr.FromInt(int(P2PoolAPIPortNumber)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		},
+	}
+
+	return obj
+}
+
+func (r *MoneroP2PoolReconciler) GetMoneroP2Pool(
+	ctx context.Context,
+	name, namespace string,
+) (*v1alpha1.MoneroP2Pool, error) {
+	obj := &v1alpha1.MoneroP2Pool{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, obj); err != nil {
+		return nil, fmt.Errorf("get %s/%s: %w", namespace, name, err)
+	}
+
+	return obj, nil
+}
+
+func (r *MoneroP2PoolReconciler) SetOwnerRef(
+	parent *v1alpha1.MoneroP2Pool,
+	obj client.Object,
+) {
+	if len(obj.GetOwnerReferences()) > 0 {
+		return
+	}
+
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion:         parent.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:               parent.GetObjectKind().GroupVersionKind().Kind,
+			Name:               parent.GetName(),
+			UID:                parent.GetUID(),
+			BlockOwnerDeletion: pointer.BoolPtr(true),
+			Controller:         pointer.BoolPtr(true),
+		},
+	})
+}
+
+func (r *MoneroP2PoolReconciler) Apply(
+	ctx context.Context,
+	obj client.Object,
+) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("get: %w", err)
+		}
+
+		if err := r.Client.Create(ctx, obj); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		return nil
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	p := client.RawPatch(
+		types.ApplyPatchType,
+		b,
+	)
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if err := r.Client.Patch(ctx, obj, p, &client.PatchOptions{
+		FieldManager: "controller",
+		Force:        pointer.BoolPtr(true),
+	}); err != nil {
+		return fmt.Errorf("patch: %w", err)
+	}
+
+	return nil
+}
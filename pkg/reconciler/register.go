@@ -1,16 +1,22 @@
 package reconciler
 
 import (
+	"context"
 	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
@@ -45,6 +51,18 @@ func RegisterReconcilers(mgr manager.Manager) error {
 		return fmt.Errorf("register secrets reconciler: %w", err)
 	}
 
+	if err := RegisterMoneroWalletRPCReconciler(mgr); err != nil {
+		return fmt.Errorf("register walletrpc reconciler: %w", err)
+	}
+
+	if err := RegisterMoneroViewWalletReconciler(mgr); err != nil {
+		return fmt.Errorf("register viewwallet reconciler: %w", err)
+	}
+
+	if err := RegisterMoneroP2PoolReconciler(mgr); err != nil {
+		return fmt.Errorf("register p2pool reconciler: %w", err)
+	}
+
 	return nil
 }
 
@@ -62,6 +80,7 @@ func RegisterMoneroNetworkReconciler(mgr manager.Manager) error {
 	if err := c.Watch(
 		&source.Kind{Type: &v1alpha1.MoneroNetwork{}},
 		&handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{},
 	); err != nil {
 		return fmt.Errorf("watch: %w", err)
 	}
@@ -83,13 +102,161 @@ func RegisterMoneroNodeSetReconciler(mgr manager.Manager) error {
 	if err := c.Watch(
 		&source.Kind{Type: &v1alpha1.MoneroNodeSet{}},
 		&handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{},
 	); err != nil {
 		return fmt.Errorf("watch: %w", err)
 	}
 
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.MoneroNodeSet{}},
+		handler.EnqueueRequestsFromMapFunc(PeeringNodeSetsMapFunc(mgr.GetClient())),
+		predicate.GenerationChangedPredicate{},
+	); err != nil {
+		return fmt.Errorf("watch peers: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.MoneroMiningNodeSet{}},
+		handler.EnqueueRequestsFromMapFunc(SoloMinedNodeSetMapFunc),
+		predicate.GenerationChangedPredicate{},
+	); err != nil {
+		return fmt.Errorf("watch solo miners: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.MoneroP2Pool{}},
+		handler.EnqueueRequestsFromMapFunc(P2PoolNodeSetMapFunc),
+		predicate.GenerationChangedPredicate{},
+	); err != nil {
+		return fmt.Errorf("watch p2pools: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(SecretNodeSetsMapFunc(mgr.GetClient())),
+	); err != nil {
+		return fmt.Errorf("watch secrets: %w", err)
+	}
+
 	return nil
 }
 
+// SecretNodeSetsMapFunc maps a Secret to the MoneroNodeSets (in the same
+// namespace) that reference it as the identity of their hidden service, so
+// that they get reconciled as soon as it's created or fixed.
+//
+func SecretNodeSetsMapFunc(c client.Client) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		nodeSets := &v1alpha1.MoneroNodeSetList{}
+		if err := c.List(context.Background(), nodeSets,
+			client.InNamespace(obj.GetNamespace()),
+		); err != nil {
+			return nil
+		}
+
+		reqs := []reconcile.Request{}
+		for idx := range nodeSets.Items {
+			nodeSet := &nodeSets.Items[idx]
+			if !NodeSetReferencesSecret(nodeSet, obj.GetName()) {
+				continue
+			}
+
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      nodeSet.Name,
+					Namespace: nodeSet.Namespace,
+				},
+			})
+		}
+
+		return reqs
+	}
+}
+
+// P2PoolNodeSetMapFunc maps a MoneroP2Pool to the MoneroNodeSet backing it so
+// that the node set gets what p2pool needs enabled.
+//
+func P2PoolNodeSetMapFunc(obj client.Object) []reconcile.Request {
+	pool, ok := obj.(*v1alpha1.MoneroP2Pool)
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      pool.Spec.NodeSetRef.Name,
+				Namespace: pool.Namespace,
+			},
+		},
+	}
+}
+
+// SoloMinedNodeSetMapFunc maps a MoneroMiningNodeSet to the MoneroNodeSet it
+// solo mines against (if any) so that the node set gets what's necessary for
+// solo mining enabled.
+//
+func SoloMinedNodeSetMapFunc(obj client.Object) []reconcile.Request {
+	miningSet, ok := obj.(*v1alpha1.MoneroMiningNodeSet)
+	if !ok || miningSet.Spec.Solo == nil {
+		return nil
+	}
+
+	namespace := miningSet.Spec.Solo.NodeSetRef.Namespace
+	if namespace == "" {
+		namespace = miningSet.Namespace
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      miningSet.Spec.Solo.NodeSetRef.Name,
+				Namespace: namespace,
+			},
+		},
+	}
+}
+
+// PeeringNodeSetsMapFunc maps a MoneroNodeSet to the MoneroNodeSets that
+// declare it as a peer so that those get their peers re-rendered.
+//
+func PeeringNodeSetsMapFunc(c client.Client) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		nodeSets := &v1alpha1.MoneroNodeSetList{}
+		if err := c.List(context.Background(), nodeSets); err != nil {
+			return nil
+		}
+
+		reqs := []reconcile.Request{}
+		for _, nodeSet := range nodeSets.Items {
+			for _, peer := range nodeSet.Spec.Peers {
+				if peer.NodeSetRef == nil || peer.NodeSetRef.Name != obj.GetName() {
+					continue
+				}
+
+				namespace := peer.NodeSetRef.Namespace
+				if namespace == "" {
+					namespace = nodeSet.Namespace
+				}
+
+				if namespace != obj.GetNamespace() {
+					continue
+				}
+
+				reqs = append(reqs, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      nodeSet.Name,
+						Namespace: nodeSet.Namespace,
+					},
+				})
+				break
+			}
+		}
+
+		return reqs
+	}
+}
+
 func RegisterMoneroMiningNodeSetReconciler(mgr manager.Manager) error {
 	c, err := controller.New("monerominingnodeset-reconciler", mgr, controller.Options{
 		Reconciler: &MoneroMiningNodeSetReconciler{
@@ -104,6 +271,198 @@ func RegisterMoneroMiningNodeSetReconciler(mgr manager.Manager) error {
 	if err := c.Watch(
 		&source.Kind{Type: &v1alpha1.MoneroMiningNodeSet{}},
 		&handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &appsv1.StatefulSet{}},
+		&handler.EnqueueRequestForOwner{
+			OwnerType:    &v1alpha1.MoneroMiningNodeSet{},
+			IsController: true,
+		},
+	); err != nil {
+		return fmt.Errorf("watch statefulsets: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &appsv1.Deployment{}},
+		&handler.EnqueueRequestForOwner{
+			OwnerType:    &v1alpha1.MoneroMiningNodeSet{},
+			IsController: true,
+		},
+	); err != nil {
+		return fmt.Errorf("watch deployments: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(SecretMiningSetsMapFunc(mgr.GetClient())),
+	); err != nil {
+		return fmt.Errorf("watch secrets: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.MoneroNodeSet{}},
+		handler.EnqueueRequestsFromMapFunc(NodeSetMiningSetsMapFunc(mgr.GetClient())),
+		predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			NodeSetSynchronizationChangedPredicate,
+		),
+	); err != nil {
+		return fmt.Errorf("watch nodesets: %w", err)
+	}
+
+	return nil
+}
+
+// NodeSetMiningSetsMapFunc maps a MoneroNodeSet to the MoneroMiningNodeSets
+// (in any namespace) that solo mine against it, so that their miners get
+// scaled as soon as it stops (or starts) being able to give them work.
+//
+func NodeSetMiningSetsMapFunc(c client.Client) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		nodeSet, ok := obj.(*v1alpha1.MoneroNodeSet)
+		if !ok {
+			return nil
+		}
+
+		miningSets := &v1alpha1.MoneroMiningNodeSetList{}
+		if err := c.List(context.Background(), miningSets); err != nil {
+			return nil
+		}
+
+		reqs := []reconcile.Request{}
+		for idx := range miningSets.Items {
+			miningSet := &miningSets.Items[idx]
+			if !IsSoloMiningAgainst(miningSet, nodeSet) {
+				continue
+			}
+
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      miningSet.Name,
+					Namespace: miningSet.Namespace,
+				},
+			})
+		}
+
+		return reqs
+	}
+}
+
+// NodeSetSynchronizationChangedPredicate lets through updates to
+// MoneroNodeSets whose synchronization status changed, leaving out the ones
+// that merely bump the height.
+//
+var NodeSetSynchronizationChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		previous, ok := e.ObjectOld.(*v1alpha1.MoneroNodeSet)
+		if !ok {
+			return false
+		}
+
+		current, ok := e.ObjectNew.(*v1alpha1.MoneroNodeSet)
+		if !ok {
+			return false
+		}
+
+		return previous.Status.Synchronized != current.Status.Synchronized
+	},
+}
+
+// SecretMiningSetsMapFunc maps a Secret to the MoneroMiningNodeSets (in the
+// same namespace) that reference it, so that their miners get rolled out
+// with the new credentials.
+//
+func SecretMiningSetsMapFunc(c client.Client) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		miningSets := &v1alpha1.MoneroMiningNodeSetList{}
+		if err := c.List(context.Background(), miningSets,
+			client.InNamespace(obj.GetNamespace()),
+		); err != nil {
+			return nil
+		}
+
+		reqs := []reconcile.Request{}
+		for idx := range miningSets.Items {
+			miningSet := &miningSets.Items[idx]
+			if !MiningSetReferencesSecret(miningSet, obj.GetName()) {
+				continue
+			}
+
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      miningSet.Name,
+					Namespace: miningSet.Namespace,
+				},
+			})
+		}
+
+		return reqs
+	}
+}
+
+func RegisterMoneroWalletRPCReconciler(mgr manager.Manager) error {
+	c, err := controller.New("monerowalletrpc-reconciler", mgr, controller.Options{
+		Reconciler: &MoneroWalletRPCReconciler{
+			Log:    mgr.GetLogger().WithName("monerowalletrpc-reconciler"),
+			Client: mgr.GetClient(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("new controller: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.MoneroWalletRPC{}},
+		&handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	return nil
+}
+
+func RegisterMoneroViewWalletReconciler(mgr manager.Manager) error {
+	c, err := controller.New("moneroviewwallet-reconciler", mgr, controller.Options{
+		Reconciler: &MoneroViewWalletReconciler{
+			Log:    mgr.GetLogger().WithName("moneroviewwallet-reconciler"),
+			Client: mgr.GetClient(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("new controller: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.MoneroViewWallet{}},
+		&handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	return nil
+}
+
+func RegisterMoneroP2PoolReconciler(mgr manager.Manager) error {
+	c, err := controller.New("monerop2pool-reconciler", mgr, controller.Options{
+		Reconciler: &MoneroP2PoolReconciler{
+			Log:    mgr.GetLogger().WithName("monerop2pool-reconciler"),
+			Client: mgr.GetClient(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("new controller: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.MoneroP2Pool{}},
+		&handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{},
 	); err != nil {
 		return fmt.Errorf("watch: %w", err)
 	}
@@ -114,8 +473,9 @@ func RegisterMoneroMiningNodeSetReconciler(mgr manager.Manager) error {
 func RegisterTorSecretsReconciler(mgr manager.Manager) error {
 	c, err := controller.New("torsecrets-reconciler", mgr, controller.Options{
 		Reconciler: &TorSecretsReconciler{
-			Log:    mgr.GetLogger().WithName("torsecrets-reconciler"),
-			Client: mgr.GetClient(),
+			Log:      mgr.GetLogger().WithName("torsecrets-reconciler"),
+			Client:   mgr.GetClient(),
+			Searches: NewTorPrefixSearches(),
 		},
 	})
 	if err != nil {
@@ -3,19 +3,56 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/cirocosta/monero-operator/pkg/tor"
 )
 
+const (
+	// TorPrefixAnnotationKey asks for the onion address that the Secret
+	// gets filled with to start with the given prefix.
+	//
+	TorPrefixAnnotationKey = "utxo.com.br/tor-prefix"
+
+	// TorPrefixTimeoutAnnotationKey bounds how long to search for the
+	// prefix for (a duration like `10m`), DefaultTorPrefixTimeout being
+	// used if not set.
+	//
+	TorPrefixTimeoutAnnotationKey = "utxo.com.br/tor-prefix-timeout"
+
+	// TorPrefixExpectedAnnotationKey reports how long the search for the
+	// prefix is expected to take.
+	//
+	TorPrefixExpectedAnnotationKey = "utxo.com.br/tor-prefix-expected"
+
+	// TorPrefixErrorAnnotationKey reports why the search for the prefix
+	// failed, with no new search taking place until it's removed.
+	//
+	TorPrefixErrorAnnotationKey = "utxo.com.br/tor-prefix-error"
+
+	DefaultTorPrefixTimeout = 10 * time.Minute
+
+	// how long to search for in order to figure out how fast keys can be
+	// searched through.
+	//
+	torPrefixRateMeasurement = time.Second
+)
+
 type TorSecretsReconciler struct {
 	client.Client
 	Log logr.Logger
+
+	// Searches runs the searches for vanity onion addresses (see
+	// FillSecretWithPrefix).
+	//
+	Searches *TorPrefixSearches
 }
 
 func (r *TorSecretsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -28,31 +65,56 @@ func (r *TorSecretsReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return EmptyResult(), fmt.Errorf("get moneronodeset: %w", err)
 	}
 
-	err = r.ReconcileSecret(ctx, secret)
+	requeueAfter, err := r.ReconcileSecret(ctx, secret)
 	if err != nil {
 		return EmptyResult(), fmt.Errorf("reconcile moneronodeset: %w", err)
 	}
 
-	return EmptyResult(), nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
+// ReconcileSecret fills the Secret with a new hidden service identity, unless
+// it already holds one.
+//
+// With a vanity prefix, the search runs in the background, the returned
+// duration indicating when to check back on it.
+//
 func (r *TorSecretsReconciler) ReconcileSecret(
 	ctx context.Context,
 	secret *corev1.Secret,
-) error {
+) (time.Duration, error) {
+	key := types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}
+
 	if r.SecretAlreadyFilled(secret) {
-		return nil
+		r.Searches.Forget(key)
+		return 0, nil
 	}
 
-	if err := r.FillSecret(secret); err != nil {
-		return fmt.Errorf("fill secret: %w", err)
+	var requeueAfter time.Duration
+
+	if prefix, found := secret.Annotations[TorPrefixAnnotationKey]; found {
+		if _, failed := secret.Annotations[TorPrefixErrorAnnotationKey]; failed {
+			r.Searches.Forget(key)
+			return 0, nil
+		}
+
+		updated, pending := r.FillSecretWithPrefix(secret, prefix)
+		if pending {
+			requeueAfter = TorPrefixPollInterval
+		}
+
+		if !updated {
+			return requeueAfter, nil
+		}
+	} else if err := r.FillSecret(secret); err != nil {
+		return 0, fmt.Errorf("fill secret: %w", err)
 	}
 
 	if err := r.Client.Update(ctx, secret); err != nil {
-		return fmt.Errorf("update secret: %w", err)
+		return 0, fmt.Errorf("update secret: %w", err)
 	}
 
-	return nil
+	return requeueAfter, nil
 }
 
 func (r *TorSecretsReconciler) FillSecret(secret *corev1.Secret) error {
@@ -65,6 +127,79 @@ func (r *TorSecretsReconciler) FillSecret(secret *corev1.Secret) error {
 	return nil
 }
 
+// FillSecretWithPrefix fills the Secret with a hidden service identity whose
+// onion address starts with `prefix`, searched for in the background (see
+// TorPrefixSearches) for up to the time limit.
+//
+// As Secrets have no status, the expected search time and, if the search
+// fails (too long of a prefix for the time limit, or no luck within it),
+// the reason are reported as annotations, in which case the Secret is left
+// unfilled.
+//
+// Whether the Secret got modified and whether the search is still going are
+// returned.
+//
+func (r *TorSecretsReconciler) FillSecretWithPrefix(
+	secret *corev1.Secret,
+	prefix string,
+) (bool, bool) {
+	key := types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}
+
+	fail := func(err error) (bool, bool) {
+		r.Log.Error(err, "tor prefix", "secret", secret.Name, "prefix", prefix)
+		secret.Annotations[TorPrefixErrorAnnotationKey] = err.Error()
+
+		return true, false
+	}
+
+	search, found := r.Searches.Get(key)
+	if !found || search.Prefix != prefix {
+		if err := tor.ValidatePrefix(prefix); err != nil {
+			return fail(fmt.Errorf("validate prefix: %w", err))
+		}
+
+		timeout := DefaultTorPrefixTimeout
+		if v, found := secret.Annotations[TorPrefixTimeoutAnnotationKey]; found {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fail(fmt.Errorf("parse timeout '%s': %w", v, err))
+			}
+
+			timeout = d
+		}
+
+		r.Log.Info("searching for tor prefix",
+			"secret", secret.Name, "prefix", prefix, "timeout", timeout,
+		)
+
+		r.Searches.Start(key, prefix, timeout)
+		return false, true
+	}
+
+	updated := false
+	if search.Expected > 0 {
+		expected := search.Expected.Round(time.Second).String()
+
+		if secret.Annotations[TorPrefixExpectedAnnotationKey] != expected {
+			secret.Annotations[TorPrefixExpectedAnnotationKey] = expected
+			updated = true
+		}
+	}
+
+	if !search.Done {
+		return updated, true
+	}
+
+	r.Searches.Forget(key)
+
+	if search.Err != nil {
+		return fail(search.Err)
+	}
+
+	secret.Data = map[string][]byte(search.Creds)
+	return true, false
+}
+
 func (r *TorSecretsReconciler) SecretAlreadyFilled(
 	secret *corev1.Secret,
 ) bool {
@@ -79,7 +214,10 @@ func (r *TorSecretsReconciler) SecretAlreadyFilled(
 		}
 	}
 
-	return true
+	// secrets filled by earlier versions of the operator had the keys
+	// swapped and in the wrong format, which tor can't make use of.
+	//
+	return tor.Credentials(secret.Data).Validate() == nil
 }
 
 func (r *TorSecretsReconciler) GetSecret(
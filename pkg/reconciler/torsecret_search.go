@@ -0,0 +1,171 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cirocosta/monero-operator/pkg/tor"
+)
+
+const (
+	// MaxTorPrefixSearches is how many searches for vanity onion
+	// addresses run at once, the others waiting for their turn.
+	//
+	MaxTorPrefixSearches = 1
+
+	// TorPrefixPollInterval is how often the Secrets being searched for
+	// get reconciled, picking up the outcome of their search.
+	//
+	TorPrefixPollInterval = 10 * time.Second
+)
+
+// TorPrefixSearch is the state of the search for a vanity onion address for
+// a Secret.
+//
+type TorPrefixSearch struct {
+	Prefix   string
+	Expected time.Duration
+
+	// Done is set once the search is over, with either the credentials
+	// found or the reason why it failed.
+	//
+	Done  bool
+	Creds tor.Credentials
+	Err   error
+
+	cancel context.CancelFunc
+}
+
+// TorPrefixSearches runs searches for vanity onion addresses in the
+// background, off the reconciliation path, so that they don't hold a
+// worker of the controller.
+//
+// Searches aren't persisted: one interrupted by a restart of the operator
+// starts over, which is fine, as the chance of finding a match is the same
+// for every key, regardless of how many were tried before.
+//
+type TorPrefixSearches struct {
+	mu       sync.Mutex
+	searches map[types.NamespacedName]*TorPrefixSearch
+	slots    chan struct{}
+	workers  int
+}
+
+func NewTorPrefixSearches() *TorPrefixSearches {
+	return &TorPrefixSearches{
+		searches: map[types.NamespacedName]*TorPrefixSearch{},
+		slots:    make(chan struct{}, MaxTorPrefixSearches),
+		workers:  TorPrefixSearchWorkers(),
+	}
+}
+
+// TorPrefixSearchWorkers is how many goroutines a search runs across: all
+// of the CPUs, with no more than MaxTorPrefixSearches searches running at
+// once, each for no longer than its time limit.
+//
+func TorPrefixSearchWorkers() int {
+	return runtime.NumCPU()
+}
+
+// Get retrieves (a copy of) the state of the search for a Secret, if any.
+//
+func (s *TorPrefixSearches) Get(key types.NamespacedName) (TorPrefixSearch, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	search, found := s.searches[key]
+	if !found {
+		return TorPrefixSearch{}, false
+	}
+
+	return *search, true
+}
+
+// Start kicks off, in the background, the search for an address starting
+// with `prefix` for a Secret, replacing any previous one.
+//
+// The search first measures how fast keys can be gone through, giving up
+// right away if, on average, it'd take longer than `timeout`.
+//
+func (s *TorPrefixSearches) Start(key types.NamespacedName, prefix string, timeout time.Duration) {
+	s.Forget(key)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	search := &TorPrefixSearch{
+		Prefix: prefix,
+		cancel: cancel,
+	}
+
+	s.mu.Lock()
+	s.searches[key] = search
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+
+		creds, err := s.search(ctx, search, prefix, timeout)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		search.Done = true
+		search.Creds = creds
+		search.Err = err
+	}()
+}
+
+// Forget cancels the search for a Secret (if still running), dropping its
+// state.
+//
+func (s *TorPrefixSearches) Forget(key types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if search, found := s.searches[key]; found {
+		search.cancel()
+		delete(s.searches, key)
+	}
+}
+
+func (s *TorPrefixSearches) search(
+	ctx context.Context,
+	search *TorPrefixSearch,
+	prefix string,
+	timeout time.Duration,
+) (tor.Credentials, error) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for a slot: %w", ctx.Err())
+	}
+
+	var (
+		rate     = tor.MeasureKeyRate(ctx, torPrefixRateMeasurement, s.workers)
+		expected = tor.ExpectedSearchTime(prefix, rate)
+	)
+
+	s.mu.Lock()
+	search.Expected = expected
+	s.mu.Unlock()
+
+	// on average, it'd take more than the time limit.
+	//
+	if expected > timeout {
+		return nil, fmt.Errorf("expected to take %s, over the time limit of %s",
+			expected.Round(time.Second), timeout,
+		)
+	}
+
+	creds, err := tor.GenerateVanityCredentials(ctx, prefix, s.workers)
+	if err != nil {
+		return nil, fmt.Errorf("generate vanity credentials: %w", err)
+	}
+
+	return creds, nil
+}
@@ -0,0 +1,381 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+	"github.com/cirocosta/monero-operator/pkg/metrics"
+	"github.com/cirocosta/monero-operator/pkg/walletrpc"
+)
+
+const (
+	// ViewWalletStatusInterval is how often the balance of a view-only
+	// wallet gets refreshed.
+	//
+	ViewWalletStatusInterval = 60 * time.Second
+
+	// ViewWalletTransfersLimit is the maximum number of incoming transfers
+	// reported in the status.
+	//
+	ViewWalletTransfersLimit = 10
+
+	// ViewWalletRefreshPeriod is how often wallet-rpc synchronizes the
+	// view-only wallet with the daemon by itself, in the background.
+	//
+	ViewWalletRefreshPeriod = 30 * time.Second
+
+	ViewWalletFilename = "view-wallet"
+)
+
+type MoneroViewWalletReconciler struct {
+	Log    logr.Logger
+	Client client.Client
+}
+
+func (r *MoneroViewWalletReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	wallet, err := r.GetMoneroViewWallet(ctx, req.Name, req.Namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			metrics.DeleteViewWallet(req.Namespace, req.Name)
+			return EmptyResult(), nil
+		}
+
+		return EmptyResult(), fmt.Errorf("get moneroviewwallet: %w", err)
+	}
+
+	err = r.ReconcileMoneroViewWallet(ctx, wallet)
+	if err != nil {
+		return EmptyResult(), fmt.Errorf("reconcile moneroviewwallet: %w", err)
+	}
+
+	return ctrl.Result{RequeueAfter: ViewWalletStatusInterval}, nil
+}
+
+func (r *MoneroViewWalletReconciler) ReconcileMoneroViewWallet(
+	ctx context.Context,
+	wallet *v1alpha1.MoneroViewWallet,
+) error {
+	password := NewCredentialsSecret(ViewWalletPasswordSecretName(wallet), wallet.Namespace)
+	if err := FillCredentialsSecret(ctx, r.Client, password); err != nil {
+		return fmt.Errorf("fill password secret: %w", err)
+	}
+
+	walletRPC := r.AssembleMoneroWalletRPC(wallet)
+
+	for _, o := range []client.Object{password, walletRPC} {
+		r.SetOwnerRef(wallet, o)
+
+		if err := r.Apply(ctx, o); err != nil {
+			return fmt.Errorf("apply '%s %s': %w",
+				o.GetObjectKind().GroupVersionKind().String(),
+				o.GetName(),
+				err,
+			)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "Succeeded",
+		Message: "wallet synchronized",
+	}
+
+	targetHeight, err := r.Observe(ctx, wallet, string(password.Data[CredentialsPasswordKey]))
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ObservationFailed"
+		condition.Message = err.Error()
+	} else {
+		if wallet.Status.Height < targetHeight {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "Synchronizing"
+			condition.Message = fmt.Sprintf("scanned %d of %d blocks", wallet.Status.Height, targetHeight)
+		}
+
+		metrics.SetViewWallet(wallet.Namespace, wallet.Name,
+			wallet.Status.Balance,
+			wallet.Status.UnlockedBalance,
+			wallet.Status.Height,
+		)
+	}
+
+	meta.SetStatusCondition(&wallet.Status.Conditions, condition)
+
+	if err := r.Client.Status().Update(ctx, wallet); err != nil {
+		return fmt.Errorf("status update: %w", err)
+	}
+
+	return nil
+}
+
+// AssembleMoneroWalletRPC creates the MoneroWalletRPC that backs the
+// view-only wallet.
+//
+func (r *MoneroViewWalletReconciler) AssembleMoneroWalletRPC(
+	wallet *v1alpha1.MoneroViewWallet,
+) *v1alpha1.MoneroWalletRPC {
+	return &v1alpha1.MoneroWalletRPC{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MoneroWalletRPC",
+			APIVersion: v1alpha1.SchemeGroupVersion.Identifier(),
+		},
+
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wallet.Name,
+			Namespace: wallet.Namespace,
+		},
+
+		Spec: v1alpha1.MoneroWalletRPCSpec{
+			NodeSetRef: wallet.Spec.NodeSetRef,
+		},
+	}
+}
+
+// Observe opens (restoring it from the keys if missing) the view-only wallet
+// in wallet-rpc, and fills the status with its balance and latest incoming
+// transfers, returning the height of the node set for telling how far along
+// the wallet is.
+//
+// Synchronizing the wallet with the daemon is left to wallet-rpc's auto
+// refresh, as scanning the chain can take far longer than a reconciliation
+// should, with the status reflecting whatever was scanned so far.
+//
+func (r *MoneroViewWalletReconciler) Observe(
+	ctx context.Context,
+	wallet *v1alpha1.MoneroViewWallet,
+	password string,
+) (uint64, error) {
+	walletRPC := &v1alpha1.MoneroWalletRPC{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      wallet.Name,
+		Namespace: wallet.Namespace,
+	}, walletRPC); err != nil {
+		return 0, fmt.Errorf("get monerowalletrpc: %w", err)
+	}
+
+	if !meta.IsStatusConditionTrue(walletRPC.Status.Conditions, "Ready") {
+		return 0, fmt.Errorf("monerowalletrpc '%s' not ready", walletRPC.Name)
+	}
+
+	rpc, err := (&MoneroWalletRPCReconciler{Client: r.Client}).WalletRPCClient(ctx, walletRPC)
+	if err != nil {
+		return 0, fmt.Errorf("wallet rpc client: %w", err)
+	}
+
+	// the wallet is only opened when it isn't already (e.g., with
+	// wallet-rpc just started), as opening it again would interrupt the
+	// scan that's going on in the background.
+	//
+	height, err := rpc.GetHeight(ctx)
+	if walletrpc.IsNotOpen(err) {
+		// only a wallet that was never created gets restored: any
+		// other failure (e.g., a wrong password) is surfaced as is
+		// rather than hidden behind a failure to overwrite the file.
+		//
+		if err := rpc.OpenWallet(ctx, ViewWalletFilename, password); err != nil {
+			if !walletrpc.IsFileNotFound(err) {
+				return 0, fmt.Errorf("open wallet: %w", err)
+			}
+
+			if err := r.RestoreWallet(ctx, rpc, wallet, password); err != nil {
+				return 0, fmt.Errorf("restore wallet: %w", err)
+			}
+		}
+
+		if err := rpc.AutoRefresh(ctx, ViewWalletRefreshPeriod); err != nil {
+			return 0, fmt.Errorf("auto refresh: %w", err)
+		}
+
+		height, err = rpc.GetHeight(ctx)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("get height: %w", err)
+	}
+
+	balance, err := rpc.GetBalance(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get balance: %w", err)
+	}
+
+	transfers, err := rpc.GetIncomingTransfers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get incoming transfers: %w", err)
+	}
+
+	wallet.Status.Height = height.Height
+	wallet.Status.Balance = balance.Balance
+	wallet.Status.UnlockedBalance = balance.UnlockedBalance
+	wallet.Status.LastIncomingTransfers = LastIncomingTransfers(
+		append(transfers.Pool, transfers.In...), ViewWalletTransfersLimit,
+	)
+
+	nodeSet, err := (&MoneroWalletRPCReconciler{Client: r.Client}).GetReferencedNodeSet(ctx, walletRPC)
+	if err != nil {
+		return 0, fmt.Errorf("get referenced nodeset: %w", err)
+	}
+
+	return nodeSet.Status.Height, nil
+}
+
+// RestoreWallet creates the view-only wallet out of the address and view key
+// in the referenced Secret.
+//
+func (r *MoneroViewWalletReconciler) RestoreWallet(
+	ctx context.Context,
+	rpc *walletrpc.Client,
+	wallet *v1alpha1.MoneroViewWallet,
+	password string,
+) error {
+	keys := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      wallet.Spec.SecretRef.Name,
+		Namespace: wallet.Namespace,
+	}, keys); err != nil {
+		return fmt.Errorf("get keys secret: %w", err)
+	}
+
+	address := string(keys.Data[v1alpha1.ViewWalletAddressKey])
+	viewKey := string(keys.Data[v1alpha1.ViewWalletViewKeyKey])
+
+	if address == "" || viewKey == "" {
+		return fmt.Errorf("secret '%s' must have both '%s' and '%s' filled",
+			keys.Name, v1alpha1.ViewWalletAddressKey, v1alpha1.ViewWalletViewKeyKey,
+		)
+	}
+
+	if _, err := rpc.GenerateFromKeys(ctx, &walletrpc.GenerateFromKeysRequestParameters{
+		RestoreHeight: wallet.Spec.RestoreHeight,
+		Filename:      ViewWalletFilename,
+		Address:       address,
+		ViewKey:       viewKey,
+		Password:      password,
+	}); err != nil {
+		return fmt.Errorf("generate from keys: %w", err)
+	}
+
+	return nil
+}
+
+// LastIncomingTransfers picks the `limit` most recent transfers.
+//
+func LastIncomingTransfers(transfers []walletrpc.Transfer, limit int) []v1alpha1.MoneroViewWalletTransfer {
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return transfers[i].Timestamp > transfers[j].Timestamp
+	})
+
+	if len(transfers) > limit {
+		transfers = transfers[:limit]
+	}
+
+	res := make([]v1alpha1.MoneroViewWalletTransfer, len(transfers))
+	for idx, transfer := range transfers {
+		res[idx] = v1alpha1.MoneroViewWalletTransfer{
+			TxID:          transfer.TxID,
+			Amount:        transfer.Amount,
+			Height:        transfer.Height,
+			Confirmations: transfer.Confirmations,
+			Timestamp:     metav1.Unix(transfer.Timestamp, 0),
+		}
+	}
+
+	return res
+}
+
+func ViewWalletPasswordSecretName(wallet *v1alpha1.MoneroViewWallet) string {
+	return wallet.Name + "-wallet"
+}
+
+func (r *MoneroViewWalletReconciler) GetMoneroViewWallet(
+	ctx context.Context,
+	name, namespace string,
+) (*v1alpha1.MoneroViewWallet, error) {
+	obj := &v1alpha1.MoneroViewWallet{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, obj); err != nil {
+		return nil, fmt.Errorf("get %s/%s: %w", namespace, name, err)
+	}
+
+	return obj, nil
+}
+
+func (r *MoneroViewWalletReconciler) SetOwnerRef(
+	parent *v1alpha1.MoneroViewWallet,
+	obj client.Object,
+) {
+	if len(obj.GetOwnerReferences()) > 0 {
+		return
+	}
+
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion:         parent.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:               parent.GetObjectKind().GroupVersionKind().Kind,
+			Name:               parent.GetName(),
+			UID:                parent.GetUID(),
+			BlockOwnerDeletion: pointer.BoolPtr(true),
+			Controller:         pointer.BoolPtr(true),
+		},
+	})
+}
+
+func (r *MoneroViewWalletReconciler) Apply(
+	ctx context.Context,
+	obj client.Object,
+) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("get: %w", err)
+		}
+
+		if err := r.Client.Create(ctx, obj); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		return nil
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	p := client.RawPatch(
+		types.ApplyPatchType,
+		b,
+	)
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if err := r.Client.Patch(ctx, obj, p, &client.PatchOptions{
+		FieldManager: "controller",
+		Force:        pointer.BoolPtr(true),
+	}); err != nil {
+		return fmt.Errorf("patch: %w", err)
+	}
+
+	return nil
+}
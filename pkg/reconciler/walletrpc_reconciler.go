@@ -0,0 +1,497 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+	"github.com/cirocosta/monero-operator/pkg/walletrpc"
+)
+
+const (
+	WalletRPCCredentialsEnvPrefix = "WALLET_RPC"
+	DaemonCredentialsEnvPrefix    = "DAEMON"
+
+	// WalletRPCStatusInterval is how often the status of a wallet-rpc
+	// instance gets refreshed.
+	//
+	WalletRPCStatusInterval = 30 * time.Second
+)
+
+type MoneroWalletRPCReconciler struct {
+	Log    logr.Logger
+	Client client.Client
+}
+
+func (r *MoneroWalletRPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	wallet, err := r.GetMoneroWalletRPC(ctx, req.Name, req.Namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return EmptyResult(), nil
+		}
+
+		return EmptyResult(), fmt.Errorf("get monerowalletrpc: %w", err)
+	}
+
+	wallet.ApplyDefaults()
+
+	err = r.ReconcileMoneroWalletRPC(ctx, wallet)
+	if err != nil {
+		return EmptyResult(), fmt.Errorf("reconcile monerowalletrpc: %w", err)
+	}
+
+	return ctrl.Result{RequeueAfter: WalletRPCStatusInterval}, nil
+}
+
+func (r *MoneroWalletRPCReconciler) ReconcileMoneroWalletRPC(
+	ctx context.Context,
+	wallet *v1alpha1.MoneroWalletRPC,
+) error {
+	objs, err := r.GenerateObjects(ctx, wallet)
+	if err != nil {
+		return fmt.Errorf("generate objects: %w", err)
+	}
+
+	for _, o := range objs {
+		r.SetOwnerRef(wallet, o)
+
+		if err := r.Apply(ctx, o); err != nil {
+			return fmt.Errorf("apply '%s %s': %w",
+				o.GetObjectKind().GroupVersionKind().String(),
+				o.GetName(),
+				err,
+			)
+		}
+	}
+
+	meta.SetStatusCondition(&wallet.Status.Conditions, r.ReadyCondition(ctx, wallet))
+
+	if err := r.Client.Status().Update(ctx, wallet); err != nil {
+		return fmt.Errorf("status update: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MoneroWalletRPCReconciler) GenerateObjects(
+	ctx context.Context,
+	wallet *v1alpha1.MoneroWalletRPC,
+) ([]client.Object, error) {
+	nodeSet, err := r.GetReferencedNodeSet(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("get referenced nodeset: %w", err)
+	}
+
+	if wallet.Spec.UseUnrestrictedRPC {
+		if !nodeSet.Spec.Monerod.UnrestrictedRPC.Enabled {
+			return nil, fmt.Errorf("moneronodeset '%s' doesn't have unrestricted rpc enabled", nodeSet.Name)
+		}
+
+		if nodeSet.Namespace != wallet.Namespace {
+			return nil, fmt.Errorf("unrestricted rpc requires moneronodeset '%s' to be in namespace '%s'",
+				nodeSet.Name, wallet.Namespace,
+			)
+		}
+	}
+
+	credentials := NewCredentialsSecret(WalletRPCCredentialsSecretName(wallet), wallet.Namespace)
+	if err := FillCredentialsSecret(ctx, r.Client, credentials); err != nil {
+		return nil, fmt.Errorf("fill credentials secret: %w", err)
+	}
+
+	return []client.Object{
+		credentials,
+		NewWalletRPCService(wallet),
+		NewWalletRPCStatefulSet(wallet, nodeSet),
+	}, nil
+}
+
+// GetReferencedNodeSet retrieves the MoneroNodeSet that wallet-rpc should
+// point at, falling back to one inferred purely from the reference when
+// there's no client to look it up with.
+//
+// Its unrestricted RPC interface is configured as the node set's reconciler
+// ends up configuring it (see EffectiveUnrestrictedRPC), rather than as
+// stored, so that both agree on whether it's enabled and requires a login.
+//
+func (r *MoneroWalletRPCReconciler) GetReferencedNodeSet(
+	ctx context.Context,
+	wallet *v1alpha1.MoneroWalletRPC,
+) (*v1alpha1.MoneroNodeSet, error) {
+	namespace := wallet.Spec.NodeSetRef.Namespace
+	if namespace == "" {
+		namespace = wallet.Namespace
+	}
+
+	if r.Client == nil {
+		nodeSet := &v1alpha1.MoneroNodeSet{}
+		nodeSet.Name = wallet.Spec.NodeSetRef.Name
+		nodeSet.Namespace = namespace
+		nodeSet.Spec.Monerod.UnrestrictedRPC.Enabled = wallet.Spec.UseUnrestrictedRPC
+		nodeSet.ApplyDefaults()
+
+		return nodeSet, nil
+	}
+
+	nodeSet := &v1alpha1.MoneroNodeSet{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      wallet.Spec.NodeSetRef.Name,
+		Namespace: namespace,
+	}, nodeSet); err != nil {
+		return nil, fmt.Errorf("get %s/%s: %w", namespace, wallet.Spec.NodeSetRef.Name, err)
+	}
+
+	nodeSet.ApplyDefaults()
+
+	rpc, err := EffectiveUnrestrictedRPC(ctx, r.Client, nodeSet)
+	if err != nil {
+		return nil, fmt.Errorf("effective unrestricted rpc: %w", err)
+	}
+
+	nodeSet.Spec.Monerod.UnrestrictedRPC = rpc.MonerodUnrestrictedRPCConfig
+	return nodeSet, nil
+}
+
+// ReadyCondition checks whether wallet-rpc is serving requests, updating the
+// wallet height in the status if a wallet happens to be opened.
+//
+func (r *MoneroWalletRPCReconciler) ReadyCondition(
+	ctx context.Context,
+	wallet *v1alpha1.MoneroWalletRPC,
+) metav1.Condition {
+	condition := metav1.Condition{
+		Type:   "Ready",
+		Status: metav1.ConditionFalse,
+	}
+
+	rpc, err := r.WalletRPCClient(ctx, wallet)
+	if err != nil {
+		condition.Reason = "ClientFailed"
+		condition.Message = err.Error()
+		return condition
+	}
+
+	if _, err := rpc.GetVersion(ctx); err != nil {
+		condition.Reason = "Unreachable"
+		condition.Message = err.Error()
+		return condition
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "Succeeded"
+	condition.Message = "wallet-rpc serving requests"
+
+	height, err := rpc.GetHeight(ctx)
+	if err != nil {
+		condition.Message = "wallet-rpc serving requests, no wallet opened"
+		return condition
+	}
+
+	wallet.Status.Height = height.Height
+	return condition
+}
+
+func (r *MoneroWalletRPCReconciler) WalletRPCClient(
+	ctx context.Context,
+	wallet *v1alpha1.MoneroWalletRPC,
+) (*walletrpc.Client, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      WalletRPCCredentialsSecretName(wallet),
+		Namespace: wallet.Namespace,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("get credentials: %w", err)
+	}
+
+	return walletrpc.NewClient(WalletRPCAddress(wallet),
+		walletrpc.WithCredentials(
+			string(secret.Data[CredentialsUsernameKey]),
+			string(secret.Data[CredentialsPasswordKey]),
+		),
+	)
+}
+
+func (r *MoneroWalletRPCReconciler) GetMoneroWalletRPC(
+	ctx context.Context,
+	name, namespace string,
+) (*v1alpha1.MoneroWalletRPC, error) {
+	obj := &v1alpha1.MoneroWalletRPC{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, obj); err != nil {
+		return nil, fmt.Errorf("get %s/%s: %w", namespace, name, err)
+	}
+
+	return obj, nil
+}
+
+func (r *MoneroWalletRPCReconciler) SetOwnerRef(
+	parent *v1alpha1.MoneroWalletRPC,
+	obj client.Object,
+) {
+	if len(obj.GetOwnerReferences()) > 0 {
+		return
+	}
+
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion:         parent.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:               parent.GetObjectKind().GroupVersionKind().Kind,
+			Name:               parent.GetName(),
+			UID:                parent.GetUID(),
+			BlockOwnerDeletion: pointer.BoolPtr(true),
+			Controller:         pointer.BoolPtr(true),
+		},
+	})
+}
+
+func (r *MoneroWalletRPCReconciler) Apply(
+	ctx context.Context,
+	obj client.Object,
+) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("get: %w", err)
+		}
+
+		if err := r.Client.Create(ctx, obj); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		return nil
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	p := client.RawPatch(
+		types.ApplyPatchType,
+		b,
+	)
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if err := r.Client.Patch(ctx, obj, p, &client.PatchOptions{
+		FieldManager: "controller",
+		Force:        pointer.BoolPtr(true),
+	}); err != nil {
+		return fmt.Errorf("patch: %w", err)
+	}
+
+	return nil
+}
+
+func WalletRPCCredentialsSecretName(wallet *v1alpha1.MoneroWalletRPC) string {
+	return wallet.Name + "-rpc"
+}
+
+func WalletRPCServiceName(wallet *v1alpha1.MoneroWalletRPC) string {
+	return wallet.Name
+}
+
+func WalletRPCAddress(wallet *v1alpha1.MoneroWalletRPC) string {
+	return fmt.Sprintf("http://%s.%s:%d",
+		WalletRPCServiceName(wallet), wallet.Namespace, WalletRPCPortNumber,
+	)
+}
+
+// DaemonAddress is the address of the node set's RPC interface that
+// wallet-rpc should use: the unrestricted one if requested, or the
+// restricted one otherwise.
+//
+func DaemonAddress(nodeSet *v1alpha1.MoneroNodeSet, unrestricted bool) string {
+	ports := Ports(nodeSet)
+
+	if unrestricted {
+		return fmt.Sprintf("%s.%s:%d", RPCServiceName(nodeSet), nodeSet.Namespace, ports.RPC)
+	}
+
+	return fmt.Sprintf("%s.%s:%d", MoneroServiceName(nodeSet), nodeSet.Namespace, ports.Restricted)
+}
+
+func NewWalletRPCContainer(
+	wallet *v1alpha1.MoneroWalletRPC,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) corev1.Container {
+	defaultArgs := append([]string{
+		"--non-interactive",
+		"--log-file=/dev/stdout",
+		"--wallet-dir=" + WalletDataVolumeMountPath,
+
+		"--rpc-bind-ip=0.0.0.0",
+		fmt.Sprintf("--rpc-bind-port=%d", WalletRPCPortNumber),
+		"--confirm-external-bind",
+		"--rpc-login=" + CredentialsLogin(WalletRPCCredentialsEnvPrefix),
+
+		"--daemon-address=" + DaemonAddress(nodeSet, wallet.Spec.UseUnrestrictedRPC),
+	}, NetworkFlags(nodeSet)...)
+
+	env := CredentialsEnv(WalletRPCCredentialsEnvPrefix, WalletRPCCredentialsSecretName(wallet))
+
+	if wallet.Spec.UseUnrestrictedRPC {
+		defaultArgs = append(defaultArgs, "--trusted-daemon")
+
+		if !nodeSet.Spec.Monerod.UnrestrictedRPC.DisableLogin {
+			defaultArgs = append(defaultArgs,
+				"--daemon-login="+CredentialsLogin(DaemonCredentialsEnvPrefix),
+			)
+
+			env = append(env, CredentialsEnv(
+				DaemonCredentialsEnvPrefix, RPCCredentialsSecretName(nodeSet),
+			)...)
+		}
+	} else {
+		defaultArgs = append(defaultArgs, "--untrusted-daemon")
+	}
+
+	command := append([]string{
+		"monero-wallet-rpc",
+	}, MergedSlice(defaultArgs, wallet.Spec.Args)...)
+
+	return corev1.Container{
+		Name:    WalletRPCContainerName,
+		Image:   wallet.Spec.Image,
+		Command: command,
+		Env:     env,
+		ReadinessProbe: &corev1.Probe{
+			PeriodSeconds:       15,
+			InitialDelaySeconds: 5,
+			FailureThreshold:    5,
+			Handler: corev1.Handler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromString(WalletRPCPortName),
+				},
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          WalletRPCPortName,
+				ContainerPort: int32(WalletRPCPortNumber),
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      WalletDataVolumeName,
+				MountPath: WalletDataVolumeMountPath,
+			},
+		},
+	}
+}
+
+func NewWalletRPCStatefulSet(
+	wallet *v1alpha1.MoneroWalletRPC,
+	nodeSet *v1alpha1.MoneroNodeSet,
+) *appsv1.StatefulSet {
+	obj := &appsv1.StatefulSet{}
+
+	obj.TypeMeta = metav1.TypeMeta{
+		Kind:       "StatefulSet",
+		APIVersion: appsv1.SchemeGroupVersion.Identifier(),
+	}
+
+	obj.ObjectMeta = metav1.ObjectMeta{
+		Name:      wallet.Name,
+		Namespace: wallet.Namespace,
+	}
+
+	claim := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: WalletDataVolumeName,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteOnce,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(wallet.Spec.DiskSize),
+				},
+			},
+		},
+	}
+
+	if wallet.Spec.StorageClass != "" {
+		claim.Spec.StorageClassName = pointer.StringPtr(wallet.Spec.StorageClass)
+	}
+
+	obj.Spec = appsv1.StatefulSetSpec{
+		ServiceName:          WalletRPCServiceName(wallet),
+		Replicas:             pointer.Int32Ptr(1),
+		RevisionHistoryLimit: pointer.Int32Ptr(0),
+		Selector: &metav1.LabelSelector{
+			MatchLabels: AppLabel(wallet.Name),
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: AppLabel(wallet.Name),
+			},
+			Spec: corev1.PodSpec{
+				TerminationGracePeriodSeconds: pointer.Int64Ptr(60),
+				Containers: []corev1.Container{
+					NewWalletRPCContainer(wallet, nodeSet),
+				},
+			},
+		},
+		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+			claim,
+		},
+	}
+
+	return obj
+}
+
+func NewWalletRPCService(wallet *v1alpha1.MoneroWalletRPC) *corev1.Service {
+	obj := &corev1.Service{}
+
+	obj.TypeMeta = metav1.TypeMeta{
+		Kind:       "Service",
+		APIVersion: corev1.SchemeGroupVersion.Identifier(),
+	}
+
+	l := AppLabel(wallet.Name)
+
+	obj.ObjectMeta = metav1.ObjectMeta{
+		Name:      WalletRPCServiceName(wallet),
+		Namespace: wallet.Namespace,
+		Labels:    l,
+	}
+
+	obj.Spec = corev1.ServiceSpec{
+		Selector: l,
+		Ports: []corev1.ServicePort{
+			{
+				Name:       WalletRPCPortName,
+				Port:       int32(WalletRPCPortNumber),
+				TargetPort: intstr.FromInt(int(WalletRPCPortNumber)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		},
+	}
+
+	return obj
+}
@@ -0,0 +1,224 @@
+package reconciler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1alpha1 "github.com/cirocosta/monero-operator/pkg/apis/utxo.com.br/v1alpha1"
+)
+
+// XmrigJSONConfig is the representation of xmrig's `config.json` that we
+// render out of the typed configuration in the MoneroMiningNodeSet.
+//
+// Fields left empty are omitted so that xmrig falls back to its own
+// defaults.
+//
+type XmrigJSONConfig struct {
+	Autosave    bool                 `json:"autosave"`
+	DonateLevel *int32               `json:"donate-level,omitempty"`
+	HTTP        XmrigJSONHTTPConfig  `json:"http"`
+	CPU         XmrigJSONCPUConfig   `json:"cpu"`
+	RandomX     XmrigJSONRandomX     `json:"randomx"`
+	Pools       []XmrigJSONPoolEntry `json:"pools"`
+}
+
+type XmrigJSONHTTPConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Host        string `json:"host,omitempty"`
+	Port        uint16 `json:"port,omitempty"`
+	AccessToken string `json:"access-token,omitempty"`
+	Restricted  *bool  `json:"restricted,omitempty"`
+}
+
+type XmrigJSONCPUConfig struct {
+	Enabled        *bool  `json:"enabled,omitempty"`
+	HugePages      *bool  `json:"huge-pages,omitempty"`
+	MaxThreadsHint *int32 `json:"max-threads-hint,omitempty"`
+	Priority       *int32 `json:"priority,omitempty"`
+	Yield          *bool  `json:"yield,omitempty"`
+	RX             []int  `json:"rx,omitempty"`
+}
+
+type XmrigJSONRandomX struct {
+	Init       *int32 `json:"init,omitempty"`
+	Mode       string `json:"mode,omitempty"`
+	OneGBPages bool   `json:"1gb-pages"`
+	NUMA       *bool  `json:"numa,omitempty"`
+	WrMSR      *bool  `json:"wrmsr,omitempty"`
+	RdMSR      *bool  `json:"rdmsr,omitempty"`
+}
+
+type XmrigJSONPoolEntry struct {
+	URL       string `json:"url"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	Keepalive bool   `json:"keepalive"`
+	Nicehash  bool   `json:"nicehash"`
+	TLS       bool   `json:"tls"`
+	Daemon    bool   `json:"daemon,omitempty"`
+}
+
+// RenderXmrigConfig generates the contents of `config.json` for the miner at
+// index `idx`.
+//
+func RenderXmrigConfig(t *MinerTemplate, idx int) ([]byte, error) {
+	config := t.MiningSet.Spec.Xmrig.Config
+
+	c := XmrigJSONConfig{
+		DonateLevel: config.DonateLevel,
+		HTTP: XmrigJSONHTTPConfig{
+			Enabled:     true,
+			Host:        "0.0.0.0",
+			Port:        XmrigHTTPPortNumber,
+			AccessToken: config.HTTP.AccessToken,
+			Restricted:  config.HTTP.Restricted,
+		},
+		CPU: XmrigJSONCPUConfig{
+			Enabled:        config.CPU.Enabled,
+			HugePages:      config.CPU.HugePages,
+			MaxThreadsHint: config.CPU.MaxThreadsHint,
+			Priority:       config.CPU.Priority,
+			Yield:          config.CPU.Yield,
+		},
+		RandomX: XmrigJSONRandomX{
+			Init:       config.RandomX.InitThreads,
+			Mode:       config.RandomX.Mode,
+			OneGBPages: config.RandomX.OneGBPages,
+			NUMA:       config.RandomX.NUMA,
+		},
+		Pools: make([]XmrigJSONPoolEntry, len(config.Pools)),
+	}
+
+	switch HugePagesSize(t.MiningSet) {
+	case v1alpha1.HugePageSize1Gi:
+		c.RandomX.OneGBPages = true
+		fallthrough
+	case v1alpha1.HugePageSize2Mi:
+		c.CPU.HugePages = pointer.BoolPtr(true)
+	}
+
+	// with the registers already set by the init container, there's no
+	// point in xmrig trying (and failing, as it's not privileged) to set
+	// or restore them.
+	//
+	if perf := t.MiningSet.Spec.Performance; perf != nil && perf.MSR != nil {
+		c.RandomX.WrMSR = pointer.BoolPtr(false)
+		c.RandomX.RdMSR = pointer.BoolPtr(false)
+	}
+
+	// each entry is a thread without affinity, so that xmrig sticks to
+	// the share of the cpu budget the miner gets.
+	//
+	if threads := MinerThreads(t.MiningSet); threads > 0 {
+		c.CPU.RX = make([]int, threads)
+		for i := range c.CPU.RX {
+			c.CPU.RX[i] = -1
+		}
+	}
+
+	for i, pool := range config.Pools {
+		user, pass, err := t.PoolCredentials(pool, idx)
+		if err != nil {
+			return nil, fmt.Errorf("pool '%s': %w", pool.URL, err)
+		}
+
+		c.Pools[i] = XmrigJSONPoolEntry{
+			URL:       pool.URL,
+			User:      user,
+			Pass:      pass,
+			Keepalive: pool.Keepalive,
+			Nicehash:  pool.Nicehash,
+			TLS:       pool.TLS,
+			Daemon:    pool.Daemon,
+		}
+	}
+
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	return b, nil
+}
+
+func XmrigConfigSecretName(miningSet *v1alpha1.MoneroMiningNodeSet) string {
+	return miningSet.Name + "-xmrig"
+}
+
+const XmrigConfigFileExtension = ".json"
+
+// XmrigConfigFilename is the name of the file holding the configuration of
+// the miner at `idx`, named after its pod so that it can pick it by itself.
+//
+func XmrigConfigFilename(miningSet *v1alpha1.MoneroMiningNodeSet, idx int) string {
+	return MinerPodName(miningSet, idx) + XmrigConfigFileExtension
+}
+
+// NewXmrigConfigSecret renders the xmrig configuration of every miner in the
+// set into a single Secret (as it carries pool credentials), keyed by
+// `<set>-miners-<idx>.json`.
+//
+func NewXmrigConfigSecret(t *MinerTemplate) (*corev1.Secret, error) {
+	var (
+		miningSet = t.MiningSet
+		data      = map[string][]byte{}
+	)
+
+	for i := 0; i < int(miningSet.Spec.Replicas); i++ {
+		b, err := RenderXmrigConfig(t, i)
+		if err != nil {
+			return nil, fmt.Errorf("render config '%d': %w", i, err)
+		}
+
+		data[XmrigConfigFilename(miningSet, i)] = b
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      XmrigConfigSecretName(miningSet),
+			Namespace: miningSet.Namespace,
+		},
+		Data: data,
+	}, nil
+}
+
+// SecretDigest serializes the contents of a Secret in a stable manner so
+// that they can be hashed.
+//
+func SecretDigest(secret *corev1.Secret) string {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key + "\n")
+		b.Write(secret.Data[key])
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// ConfigHash provides a digest of some content so that changes to it can
+// be reflected in a pod template, triggering a rollout.
+//
+func ConfigHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
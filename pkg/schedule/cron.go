@@ -0,0 +1,280 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed cron expression in its standard five-field form:
+//
+//	minute hour day-of-month month day-of-week
+//
+// Each field takes `*`, single values, ranges (`1-5`), lists (`1,3,5`) and
+// steps (`*/15`, `0-30/10`). Months and days of the week can also be given
+// by their three-letter names (`jan`, `mon`), with `0` and `7` both being
+// Sunday.
+//
+type Cron struct {
+	minute [60]bool
+	hour   [24]bool
+	dom    [32]bool
+	month  [13]bool
+	dow    [7]bool
+
+	// domAny and dowAny track whether day-of-month and day-of-week were
+	// left unrestricted, as when both are restricted a day only needs to
+	// match one of them.
+	//
+	domAny bool
+	dowAny bool
+
+	// everyMinute tracks whether all minutes of an hour match, letting
+	// runs of matching times be skipped through an hour at a time.
+	//
+	everyMinute bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day-of-month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{name: "day-of-week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCron parses a five-field cron expression.
+//
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	c := &Cron{
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+
+	var err error
+
+	if err = parseField(fields[0], minuteField, c.minute[:]); err != nil {
+		return nil, err
+	}
+
+	if err = parseField(fields[1], hourField, c.hour[:]); err != nil {
+		return nil, err
+	}
+
+	if err = parseField(fields[2], domField, c.dom[:]); err != nil {
+		return nil, err
+	}
+
+	if err = parseField(fields[3], monthField, c.month[:]); err != nil {
+		return nil, err
+	}
+
+	dow := make([]bool, 8)
+	if err = parseField(fields[4], dowField, dow); err != nil {
+		return nil, err
+	}
+
+	copy(c.dow[:], dow)
+	c.dow[0] = c.dow[0] || dow[7]
+
+	c.everyMinute = true
+	for _, match := range c.minute {
+		c.everyMinute = c.everyMinute && match
+	}
+
+	return c, nil
+}
+
+func parseField(expr string, f field, set []bool) error {
+	for _, part := range strings.Split(expr, ",") {
+		if err := parseRange(part, f, set); err != nil {
+			return fmt.Errorf("%s '%s': %w", f.name, expr, err)
+		}
+	}
+
+	return nil
+}
+
+func parseRange(expr string, f field, set []bool) error {
+	var (
+		step = 1
+		err  error
+	)
+
+	if idx := strings.Index(expr, "/"); idx >= 0 {
+		step, err = strconv.Atoi(expr[idx+1:])
+		if err != nil || step <= 0 {
+			return fmt.Errorf("invalid step '%s'", expr[idx+1:])
+		}
+
+		expr = expr[:idx]
+	}
+
+	low, high := f.min, f.max
+
+	switch {
+	case expr == "*":
+	case strings.Contains(expr, "-"):
+		bounds := strings.SplitN(expr, "-", 2)
+
+		if low, err = parseValue(bounds[0], f); err != nil {
+			return err
+		}
+
+		if high, err = parseValue(bounds[1], f); err != nil {
+			return err
+		}
+
+		if low > high {
+			return fmt.Errorf("invalid range '%s'", expr)
+		}
+	default:
+		if low, err = parseValue(expr, f); err != nil {
+			return err
+		}
+
+		if step == 1 {
+			high = low
+		}
+	}
+
+	for v := low; v <= high; v += step {
+		set[v] = true
+	}
+
+	return nil
+}
+
+func parseValue(expr string, f field) (int, error) {
+	if v, found := f.names[strings.ToLower(expr)]; found {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", expr)
+	}
+
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// Next finds the first time after `t` (in the same location) that matches
+// the expression, or the zero time if there's none within the next five
+// years (e.g., February 30th).
+//
+// Times skipped as clocks go forward never match, and those repeated as
+// clocks go back only match the first time around.
+//
+func (c *Cron) Next(t time.Time) time.Time {
+	var (
+		loc   = t.Location()
+		limit = t.AddDate(5, 0, 0)
+	)
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		switch {
+		case !c.month[t.Month()]:
+			t = midnight(t.Year(), t.Month()+1, 1, loc)
+		case !c.dayMatches(t):
+			t = midnight(t.Year(), t.Month(), t.Day()+1, loc)
+		case !c.hour[t.Hour()] || repeated(t):
+			t = nextHour(t)
+		case !c.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// NextMiss finds the first time after `t` that doesn't match the expression
+// (see Next), or the zero time if there's none before `limit`.
+//
+func (c *Cron) NextMiss(t, limit time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		if !c.month[t.Month()] || !c.dayMatches(t) ||
+			!c.hour[t.Hour()] || repeated(t) || !c.minute[t.Minute()] {
+			return t
+		}
+
+		if c.everyMinute {
+			t = nextHour(t)
+		} else {
+			t = t.Add(time.Minute)
+		}
+	}
+
+	return time.Time{}
+}
+
+// midnight is the start of a day or, with midnight skipped as clocks go
+// forward, the first time of that day.
+//
+// Moving through time like this (rather than through time.Date alone)
+// keeps from going backwards, as time.Date resolves a time that's skipped
+// to one before the gap.
+//
+func midnight(year int, month time.Month, day int, loc *time.Location) time.Time {
+	var (
+		t    = time.Date(year, month, day, 0, 0, 0, 0, loc)
+		noon = time.Date(year, month, day, 12, 0, 0, 0, loc)
+	)
+
+	for t.Day() != noon.Day() {
+		t = nextHour(t)
+	}
+
+	return t
+}
+
+// nextHour is the start of the hour that follows the one `t` is in (e.g., 3am
+// after 1:30am as clocks go forward from 2am to 3am).
+//
+func nextHour(t time.Time) time.Time {
+	return t.Truncate(time.Minute).Add(time.Duration(60-t.Minute()) * time.Minute)
+}
+
+// repeated tells whether `t` is in an hour that's happening for the second
+// time, as clocks went back by an hour.
+//
+func repeated(t time.Time) bool {
+	earlier := t.Add(-time.Hour)
+	return earlier.Hour() == t.Hour() && earlier.Day() == t.Day()
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[t.Weekday()]
+
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+
+	return dom || dow
+}
@@ -0,0 +1,255 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func values(set []bool) []int {
+	res := []int{}
+	for v, found := range set {
+		if found {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+func TestParseCron(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expr     string
+		field    func(c *Cron) []bool
+		expected []int
+	}{
+		{
+			name:     "single value",
+			expr:     "5 * * * *",
+			field:    func(c *Cron) []bool { return c.minute[:] },
+			expected: []int{5},
+		},
+		{
+			name:     "range",
+			expr:     "0 9-12 * * *",
+			field:    func(c *Cron) []bool { return c.hour[:] },
+			expected: []int{9, 10, 11, 12},
+		},
+		{
+			name:     "step over everything",
+			expr:     "*/15 * * * *",
+			field:    func(c *Cron) []bool { return c.minute[:] },
+			expected: []int{0, 15, 30, 45},
+		},
+		{
+			name:     "step over a range",
+			expr:     "0-30/10 * * * *",
+			field:    func(c *Cron) []bool { return c.minute[:] },
+			expected: []int{0, 10, 20, 30},
+		},
+		{
+			name:     "step from a value",
+			expr:     "0 5/6 * * *",
+			field:    func(c *Cron) []bool { return c.hour[:] },
+			expected: []int{5, 11, 17, 23},
+		},
+		{
+			name:     "list",
+			expr:     "0 0 1,15,31 * *",
+			field:    func(c *Cron) []bool { return c.dom[:] },
+			expected: []int{1, 15, 31},
+		},
+		{
+			name:     "month names",
+			expr:     "0 0 * jan,Jun-AUG *",
+			field:    func(c *Cron) []bool { return c.month[:] },
+			expected: []int{1, 6, 7, 8},
+		},
+		{
+			name:     "day-of-week names",
+			expr:     "0 0 * * mon-fri",
+			field:    func(c *Cron) []bool { return c.dow[:] },
+			expected: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "sunday as 7",
+			expr:     "0 0 * * 5-7",
+			field:    func(c *Cron) []bool { return c.dow[:] },
+			expected: []int{0, 5, 6},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := ParseCron(tc.expr)
+			if err != nil {
+				t.Fatalf("parse cron: %v", err)
+			}
+
+			if actual := values(tc.field(c)); !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"1,,2 * * * *",
+		"* * * * mon-",
+		"* * * foo *",
+		"* * * * jan",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if c, err := ParseCron(expr); err == nil {
+				t.Fatalf("expected error, got %+v", c)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+
+	utc := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "strictly after",
+			expr:     "* * * * *",
+			from:     utc(2021, 8, 1, 10, 0),
+			expected: utc(2021, 8, 1, 10, 1),
+		},
+		{
+			name:     "within a minute",
+			expr:     "* * * * *",
+			from:     utc(2021, 8, 1, 10, 0).Add(30 * time.Second),
+			expected: utc(2021, 8, 1, 10, 1),
+		},
+		{
+			name:     "across the year",
+			expr:     "0 0 1 jan *",
+			from:     utc(2021, 8, 1, 10, 0),
+			expected: utc(2022, 1, 1, 0, 0),
+		},
+		{
+			name:     "leap day",
+			expr:     "0 0 29 feb *",
+			from:     utc(2021, 3, 1, 0, 0),
+			expected: utc(2024, 2, 29, 0, 0),
+		},
+		{
+			name: "day that doesn't exist",
+			expr: "0 0 30 feb *",
+			from: utc(2021, 3, 1, 0, 0),
+		},
+		{
+			name:     "day-of-month only",
+			expr:     "0 0 13 * *",
+			from:     utc(2021, 8, 14, 0, 0),
+			expected: utc(2021, 9, 13, 0, 0),
+		},
+		{
+			name:     "day-of-week only",
+			expr:     "0 0 * * fri",
+			from:     utc(2021, 8, 14, 0, 0),
+			expected: utc(2021, 8, 20, 0, 0),
+		},
+		{
+			name:     "day-of-month or day-of-week, the latter first",
+			expr:     "0 0 13 * fri",
+			from:     utc(2021, 9, 4, 0, 0),
+			expected: utc(2021, 9, 10, 0, 0),
+		},
+		{
+			name:     "day-of-month or day-of-week, the former first",
+			expr:     "0 0 13 * fri",
+			from:     utc(2021, 9, 11, 0, 0),
+			expected: utc(2021, 9, 13, 0, 0),
+		},
+		{
+			name:     "in a time zone",
+			expr:     "0 9 * * *",
+			from:     time.Date(2021, 8, 1, 10, 0, 0, 0, newYork),
+			expected: utc(2021, 8, 2, 13, 0),
+		},
+		{
+			name:     "clocks going forward, past the gap",
+			expr:     "0 5 * * *",
+			from:     time.Date(2021, 3, 14, 0, 30, 0, 0, newYork),
+			expected: utc(2021, 3, 14, 9, 0),
+		},
+		{
+			name:     "clocks going forward, within the gap",
+			expr:     "30 2 * * *",
+			from:     time.Date(2021, 3, 14, 0, 0, 0, 0, newYork),
+			expected: utc(2021, 3, 15, 6, 30),
+		},
+		{
+			name:     "clocks going forward, across the gap",
+			expr:     "*/30 * * * *",
+			from:     time.Date(2021, 3, 14, 1, 45, 0, 0, newYork),
+			expected: utc(2021, 3, 14, 7, 0),
+		},
+		{
+			name:     "clocks going back, first time around",
+			expr:     "30 1 * * *",
+			from:     time.Date(2021, 11, 7, 0, 0, 0, 0, newYork),
+			expected: utc(2021, 11, 7, 5, 30),
+		},
+		{
+			name:     "clocks going back, second time around",
+			expr:     "30 1 * * *",
+			from:     utc(2021, 11, 7, 5, 30).In(newYork),
+			expected: utc(2021, 11, 8, 6, 30),
+		},
+		{
+			name:     "clocks going back, hourly",
+			expr:     "0 * * * *",
+			from:     utc(2021, 11, 7, 5, 0).In(newYork),
+			expected: utc(2021, 11, 7, 7, 0),
+		},
+		{
+			name:     "clocks going back, within the hour",
+			expr:     "*/15 * * * *",
+			from:     utc(2021, 11, 7, 5, 45).In(newYork),
+			expected: utc(2021, 11, 7, 7, 0),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := ParseCron(tc.expr)
+			if err != nil {
+				t.Fatalf("parse cron: %v", err)
+			}
+
+			actual := c.Next(tc.from)
+			if !actual.Equal(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+
+			if !actual.IsZero() && actual.Location() != tc.from.Location() {
+				t.Fatalf("expected location %v, got %v", tc.from.Location(), actual.Location())
+			}
+		})
+	}
+}
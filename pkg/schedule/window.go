@@ -0,0 +1,106 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	// embedded so that time zones can be loaded regardless of the base
+	// image having a zoneinfo database or not.
+	//
+	_ "time/tzdata"
+)
+
+// Horizon is how far ahead transitions are looked for: windows that keep on
+// overlapping beyond it are considered to never end.
+//
+const Horizon = 366 * 24 * time.Hour
+
+// Window is a period of time starting whenever the cron expression matches
+// and lasting for a fixed duration.
+//
+type Window struct {
+	Start    *Cron
+	Duration time.Duration
+}
+
+// NewWindow parses the cron expression that a window starts at.
+//
+func NewWindow(start string, duration time.Duration) (Window, error) {
+	if duration <= 0 {
+		return Window{}, fmt.Errorf("duration must be positive")
+	}
+
+	cron, err := ParseCron(start)
+	if err != nil {
+		return Window{}, fmt.Errorf("parse cron '%s': %w", start, err)
+	}
+
+	return Window{Start: cron, Duration: duration}, nil
+}
+
+// State tells whether `now` falls within any of the windows, and when that's
+// going to change next (the zero time meaning never, as far as Horizon
+// goes).
+//
+// Overlapping or back-to-back windows are treated as a single one.
+//
+func State(windows []Window, now time.Time) (bool, time.Time) {
+	var (
+		end   = now
+		limit = now.Add(Horizon)
+	)
+
+	for extended := true; extended; {
+		extended = false
+
+		for _, w := range windows {
+			until := w.CoveredUntil(end, limit)
+			if !until.After(end) {
+				continue
+			}
+
+			end = until
+			extended = true
+
+			if end.After(limit) {
+				return true, time.Time{}
+			}
+		}
+	}
+
+	if end.After(now) {
+		return true, end
+	}
+
+	var next time.Time
+
+	for _, w := range windows {
+		start := w.Start.Next(now)
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	return false, next
+}
+
+// CoveredUntil tells until when the window keeps on covering what follows
+// `t`, going through runs of consecutive starts (e.g., every minute) at
+// once rather than one start at a time.
+//
+// `t` itself is returned if it's not within the window, and a time after
+// `limit` if the window covers all the way to it.
+//
+func (w Window) CoveredUntil(t, limit time.Time) time.Time {
+	start := w.Start.Next(t.Add(-w.Duration))
+	if start.IsZero() || start.After(t) {
+		return t
+	}
+
+	miss := w.Start.NextMiss(start, limit)
+	if miss.IsZero() {
+		return limit.Add(w.Duration)
+	}
+
+	return miss.Add(-time.Minute).Add(w.Duration)
+}
@@ -0,0 +1,161 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestState(t *testing.T) {
+	type window struct {
+		start    string
+		duration time.Duration
+	}
+
+	// a monday.
+	//
+	at := func(hour, min int) time.Time {
+		return time.Date(2021, 8, 2, hour, min, 0, 0, time.UTC)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		windows []window
+		now     time.Time
+		active  bool
+		next    time.Time
+	}{
+		{
+			name: "no windows",
+			now:  at(9, 0),
+		},
+		{
+			name:    "before a window",
+			windows: []window{{"0 9 * * *", time.Hour}},
+			now:     at(8, 0),
+			next:    at(9, 0),
+		},
+		{
+			name:    "at the start of a window",
+			windows: []window{{"0 9 * * *", time.Hour}},
+			now:     at(9, 0),
+			active:  true,
+			next:    at(10, 0),
+		},
+		{
+			name:    "at the end of a window",
+			windows: []window{{"0 9 * * *", time.Hour}},
+			now:     at(10, 0),
+			next:    at(9, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:    "earliest of the next windows",
+			windows: []window{{"0 18 * * *", time.Hour}, {"0 12 * * *", time.Hour}},
+			now:     at(10, 0),
+			next:    at(12, 0),
+		},
+		{
+			name:    "overlapping windows",
+			windows: []window{{"0 9 * * *", 2 * time.Hour}, {"0 10 * * *", 2 * time.Hour}},
+			now:     at(9, 30),
+			active:  true,
+			next:    at(12, 0),
+		},
+		{
+			name:    "window within another",
+			windows: []window{{"0 9 * * *", 3 * time.Hour}, {"0 10 * * *", time.Hour}},
+			now:     at(10, 30),
+			active:  true,
+			next:    at(12, 0),
+		},
+		{
+			name:    "back-to-back windows",
+			windows: []window{{"0 10 * * *", time.Hour}, {"0 9 * * *", time.Hour}},
+			now:     at(9, 30),
+			active:  true,
+			next:    at(11, 0),
+		},
+		{
+			name:    "windows with a gap in between",
+			windows: []window{{"0 9 * * *", time.Hour}, {"1 10 * * *", time.Hour}},
+			now:     at(9, 30),
+			active:  true,
+			next:    at(10, 0),
+		},
+		{
+			name:    "window starting every minute",
+			windows: []window{{"* 9-16 * * mon-fri", time.Minute}},
+			now:     at(9, 30),
+			active:  true,
+			next:    at(17, 0),
+		},
+		{
+			name:    "window starting every minute through the week",
+			windows: []window{{"* * * * mon-fri", time.Minute}},
+			now:     at(9, 30),
+			active:  true,
+			next:    at(0, 0).AddDate(0, 0, 5),
+		},
+		{
+			name:    "always overlapping",
+			windows: []window{{"* * * * *", time.Hour}},
+			now:     at(9, 30),
+			active:  true,
+		},
+		{
+			name:    "always back-to-back",
+			windows: []window{{"0 * * * *", time.Hour}},
+			now:     at(9, 30),
+			active:  true,
+		},
+		{
+			name:    "longer than the time between starts",
+			windows: []window{{"0 0 * * *", 25 * time.Hour}},
+			now:     at(9, 30),
+			active:  true,
+		},
+		{
+			name:    "ending within the horizon",
+			windows: []window{{"0 0 * 1-11 *", 24 * time.Hour}},
+			now:     time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+			active:  true,
+			next:    time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "next start months ahead",
+			windows: []window{{"* * * 1-11 *", time.Minute}},
+			now:     time.Date(2021, 12, 31, 12, 0, 0, 0, time.UTC),
+			next:    time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			windows := make([]Window, len(tc.windows))
+			for idx, w := range tc.windows {
+				var err error
+
+				windows[idx], err = NewWindow(w.start, w.duration)
+				if err != nil {
+					t.Fatalf("new window: %v", err)
+				}
+			}
+
+			active, next := State(windows, tc.now)
+			if active != tc.active {
+				t.Fatalf("expected active=%v, got %v", tc.active, active)
+			}
+
+			if !next.Equal(tc.next) {
+				t.Fatalf("expected next %v, got %v", tc.next, next)
+			}
+		})
+	}
+}
+
+func TestNewWindowInvalid(t *testing.T) {
+	if _, err := NewWindow("0 9 * * *", 0); err == nil {
+		t.Fatalf("expected error for a non-positive duration")
+	}
+
+	if _, err := NewWindow("0 9 * *", time.Hour); err == nil {
+		t.Fatalf("expected error for an invalid cron expression")
+	}
+}
@@ -0,0 +1,185 @@
+package stratum
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	jobReply   = `{"id":1,"jsonrpc":"2.0","error":null,"result":{"id":"1be0b7b6","job":{"blob":"0e0e","job_id":"4b4a","target":"f3220000","algo":"rx/0","height":2731800,"seed_hash":"aa"},"extensions":["algo","keepalive"],"status":"OK"}}`
+	errorReply = `{"id":1,"jsonrpc":"2.0","error":{"code":-1,"message":"Invalid payment address provided"}}`
+	emptyReply = `{"id":1,"jsonrpc":"2.0","error":null,"result":{"id":"1be0b7b6","status":"OK"}}`
+)
+
+// stratumServer is a fake pool that replies to the login with whatever it's
+// been told to, recording the request.
+//
+type stratumServer struct {
+	net.Listener
+
+	reply    string
+	requests chan loginRequest
+}
+
+func newStratumServer(t *testing.T, reply string, useTLS bool) *stratumServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	if useTLS {
+		// borrow the self-signed certificate that httptest uses.
+		//
+		certs := httptest.NewTLSServer(nil)
+		certs.Close()
+
+		listener = tls.NewListener(listener, &tls.Config{
+			Certificates: certs.TLS.Certificates,
+		})
+	}
+
+	s := &stratumServer{
+		Listener: listener,
+		reply:    reply,
+		requests: make(chan loginRequest, 1),
+	}
+
+	t.Cleanup(func() { s.Close() })
+
+	go s.serve()
+
+	return s
+}
+
+func (s *stratumServer) serve() {
+	for {
+		conn, err := s.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			line, err := bufio.NewReader(conn).ReadBytes('\n')
+			if err != nil {
+				return
+			}
+
+			req := loginRequest{}
+			if err := json.Unmarshal(line, &req); err != nil {
+				return
+			}
+
+			s.requests <- req
+
+			if s.reply == "" {
+				// hang until the prober gives up.
+				//
+				conn.Read(make([]byte, 1))
+				return
+			}
+
+			conn.Write([]byte(s.reply + "\n"))
+		}()
+	}
+}
+
+func TestProbe(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		reply string
+		tls   bool
+		err   string
+	}{
+		{
+			name:  "job",
+			reply: jobReply,
+		},
+		{
+			name:  "job over tls",
+			reply: jobReply,
+			tls:   true,
+		},
+		{
+			name:  "login rejected",
+			reply: errorReply,
+			err:   "login: Invalid payment address provided (code -1)",
+		},
+		{
+			name:  "no job",
+			reply: emptyReply,
+			err:   "login: no job received",
+		},
+		{
+			name:  "garbage",
+			reply: "HTTP/1.1 400 Bad Request",
+			err:   "unmarshal login response",
+		},
+		{
+			name: "no reply",
+			err:  "read login response",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newStratumServer(t, tc.reply, tc.tls)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			latency, err := Probe(ctx, ProbeOptions{
+				Address: server.Addr().String(),
+				TLS:     tc.tls,
+				User:    "44AFFq5kSiGBoZ4NMDwYtN18obc8AemS33DBLWs3H7otXft3XjrpDtQGv7SqSsaBYBb98uNbr2VBBEt7f2wfn3RVGQBEP3A.miner-1",
+				Pass:    "x",
+				Timeout: 500 * time.Millisecond,
+			})
+
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing '%s', got %v", tc.err, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("probe: %v", err)
+				}
+
+				if latency <= 0 {
+					t.Fatalf("expected a latency, got %s", latency)
+				}
+			}
+
+			req := <-server.requests
+			if req.Method != "login" ||
+				req.Params.Login != "44AFFq5kSiGBoZ4NMDwYtN18obc8AemS33DBLWs3H7otXft3XjrpDtQGv7SqSsaBYBb98uNbr2VBBEt7f2wfn3RVGQBEP3A.miner-1" ||
+				req.Params.Pass != "x" ||
+				req.Params.Agent != Agent {
+				t.Fatalf("unexpected login request %+v", req)
+			}
+		})
+	}
+}
+
+func TestProbeUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	address := listener.Addr().String()
+	listener.Close()
+
+	if _, err := Probe(context.Background(), ProbeOptions{Address: address}); err == nil ||
+		!strings.HasPrefix(err.Error(), "dial: ") {
+		t.Fatalf("expected dial error, got %v", err)
+	}
+}
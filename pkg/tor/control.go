@@ -1,20 +1,684 @@
 package tor
 
-// GETINFO network-liveness
-// 	250-network-liveness=up
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
 
-// GETINFO status/circuit-established
-// 	250-status/circuit-established=1
+const (
+	StatusOK = 250
 
-// GETINFO traffic/read
-// 	250-traffic/read=118758
+	// StatusEvent is the status of the replies that tor sends
+	// asynchronously, for the events subscribed to.
+	//
+	StatusEvent = 650
 
-// GETINFO traffic/written
-// 	250-traffic/written=345022
+	SignalReload   = "RELOAD"
+	SignalShutdown = "SHUTDOWN"
+	SignalDump     = "DUMP"
+	SignalDebug    = "DEBUG"
+	SignalHalt     = "HALT"
+	SignalNewNym   = "NEWNYM"
+	SignalActive   = "ACTIVE"
+	SignalDormant  = "DORMANT"
 
-//				virtual target
-//				 .      .
-// ADD_ONION NEW:ED25519-V3 Port=3333,3333
-//	250-ServiceID=cetjf747n66cst7l
-//	250-PrivateKey=RSA1024:MIICWwIBAAKBgQDEUiDokz6kspgNlQXlyPrX4O+hlxM8IyvqJdyGgRMvae/jsYYzawOyarkuN6YGa+On7Ai7/P9f1uqV/TBh5n4BmI6HP9fFU9DKqocOgv5iPY97CWo42OebpQBQjRopnaV6+YhMh4boLkmUypo/b6OA0BPG6alwFVOvWgllPH72xwIDAQABAoGAefARJKQgaqfLLdR7/0egFmpOLO3rMpUd96dgm+KcCf0g1So0ksdLWoxTTNOfualVk71Vh34TGHIi7tp+LLC/XiM4uLixXqTWOB6mnRQem7pJFq6FaU/grX9tyc+81vlD+h3VFLoTDBdThrq6j/4bodr4XWCR7PF6pJShYfvRugECQQDwdGcys/Fwgi35A7BBv55ciwo9nE3LMwN7FBnABbIue1UcQeXU8MR+4YxfaUmek7WaFkQ+1uhbnVe7jZmUw5pHAkEA0QNL5uPCcOEtb3/MOD3Fv1p+QcxIufLYyfHKeEMtP6BcHU1T/9rDj9AXHuw3iFAa9tWy6RzungFrgmwbXfN/gQJAX2TOzD63Ontq4a+k7XCn0u8WUPsBoevLJbkDvjqERgyH5339QE2EpxG0LuYGXvMayNasnIGGDVKOy8mRdINlYQJAX/bNgKMOAVnBzLG2iBQEmQjpczhiI84WLxIuzey5976ezG1WpeBBbeQy2WXHUXTGDJKtZE5+WFl6jRUps4c7AQJAUUgq4sZddC8EmFBYU1AMKUcQS5+QIuMCDcUc2yB0h6jIcm9VoOAmlfy/0OfJZfZA66gYDciidBCem2SHD3bKiA==
-//	250 OK
+	AuthMethodNull           = "NULL"
+	AuthMethodCookie         = "COOKIE"
+	AuthMethodHashedPassword = "HASHEDPASSWORD"
+
+	// OnionKeyNew makes tor generate a new v3 identity for the onion
+	// service being added.
+	//
+	OnionKeyNew = "NEW:ED25519-V3"
+
+	OnionFlagDiscardPK = "DiscardPK"
+	OnionFlagDetach    = "Detach"
+
+	// how many events get buffered before new ones get dropped.
+	//
+	controlEventsBufferSize = 64
+)
+
+// ReplyLine is a line of a reply from the control port: its text and, for
+// lines followed by a data block (e.g., `250+key=` in a GETINFO reply), the
+// contents of the block.
+//
+type ReplyLine struct {
+	Status int
+	Text   string
+	Data   string
+}
+
+// Reply is a full reply (possibly spread across many lines) from the
+// control port.
+//
+type Reply struct {
+	Status int
+	Lines  []ReplyLine
+}
+
+// Event is a reply that tor sent asynchronously, for an event subscribed to
+// via Subscribe (e.g., `CIRC`, `HS_DESC`).
+//
+type Event struct {
+	Type  string
+	Reply *Reply
+}
+
+// ControlError is the error returned when tor replies to a command with
+// a non-successful status.
+//
+type ControlError struct {
+	Status  int
+	Message string
+}
+
+func (e *ControlError) Error() string {
+	return fmt.Sprintf("tor: %d %s", e.Status, e.Message)
+}
+
+// Controller is a client for tor's control protocol (see
+// https://spec.torproject.org/control-spec), through which a running tor
+// can be queried (GETINFO) and driven (SIGNAL, ADD_ONION, ...).
+//
+// Commands are sent one at a time, each waiting for its reply. Replies to
+// events subscribed to are delivered separately, through Events.
+//
+// Once a command is interrupted (e.g., through its context), the
+// connection gets closed, as the replies to any further command could no
+// longer be told apart from the one for the interrupted command.
+//
+type Controller struct {
+	conn net.Conn
+
+	mu     sync.Mutex
+	events chan Event
+
+	// pending holds the channel that the reply to the command in flight
+	// (if any) is to be handed over to.
+	//
+	pending chan chan *Reply
+
+	// done gets closed once the connection is, with `err` set to the
+	// error that it got closed with.
+	//
+	done chan struct{}
+	err  error
+}
+
+// DialController connects to tor's control port at `address` (e.g.,
+// `127.0.0.1:9051` for `tcp`, or a path for `unix`).
+//
+// The connection still needs to be authenticated (see Authenticate) before
+// any other command gets accepted by tor.
+//
+func DialController(ctx context.Context, network, address string) (*Controller, error) {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s '%s': %w", network, address, err)
+	}
+
+	return NewController(conn), nil
+}
+
+// NewController instantiates a controller that speaks the control protocol
+// over an already established connection.
+//
+func NewController(conn net.Conn) *Controller {
+	c := &Controller{
+		conn:    conn,
+		events:  make(chan Event, controlEventsBufferSize),
+		pending: make(chan chan *Reply, 1),
+		done:    make(chan struct{}),
+	}
+
+	go c.readLoop()
+
+	return c
+}
+
+// Close closes the connection to the control port, which makes tor drop
+// the onion services that were added through it without the `Detach` flag.
+//
+// Events gets closed once the read loop notices.
+//
+func (c *Controller) Close() error {
+	return c.conn.Close()
+}
+
+// Events delivers the events subscribed to (see Subscribe). It gets closed
+// once the connection is.
+//
+// Events that aren't consumed fast enough get dropped.
+//
+func (c *Controller) Events() <-chan Event {
+	return c.events
+}
+
+// Command sends a raw command (without the trailing CRLF), retrieving its
+// reply, erroring if tor didn't reply with success.
+//
+func (c *Controller) Command(ctx context.Context, command string) (*Reply, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return nil, fmt.Errorf("closed: %w", c.err)
+	default:
+	}
+
+	// with commands going one at a time, and each taking its channel
+	// back out if the read loop didn't, there's always room for it.
+	//
+	replyC := make(chan *Reply, 1)
+	c.pending <- replyC
+
+	if _, err := io.WriteString(c.conn, command+"\r\n"); err != nil {
+		c.unregister()
+		return nil, fmt.Errorf("write: %w", err)
+	}
+
+	var reply *Reply
+
+	select {
+	case reply = <-replyC:
+	case <-c.done:
+		select {
+		case reply = <-replyC:
+		default:
+			c.unregister()
+			return nil, fmt.Errorf("read: %w", c.err)
+		}
+	case <-ctx.Done():
+		c.conn.Close()
+		c.unregister()
+		return nil, fmt.Errorf("wait for reply: %w", ctx.Err())
+	}
+
+	if reply.Status != StatusOK {
+		return reply, &ControlError{
+			Status:  reply.Status,
+			Message: reply.Lines[len(reply.Lines)-1].Text,
+		}
+	}
+
+	return reply, nil
+}
+
+// unregister takes the channel of the command in flight back out, unless the
+// read loop already took it.
+//
+func (c *Controller) unregister() {
+	select {
+	case <-c.pending:
+	default:
+	}
+}
+
+// ProtocolInfo describes how the controller can authenticate.
+//
+type ProtocolInfo struct {
+	AuthMethods []string
+	CookieFile  string
+	Version     string
+}
+
+// ProtocolInfo retrieves how the controller can authenticate. It's one of
+// the few commands that tor accepts before authentication, and only once.
+//
+func (c *Controller) ProtocolInfo(ctx context.Context) (*ProtocolInfo, error) {
+	reply, err := c.Command(ctx, "PROTOCOLINFO 1")
+	if err != nil {
+		return nil, fmt.Errorf("protocolinfo: %w", err)
+	}
+
+	info := &ProtocolInfo{}
+
+	for _, line := range reply.Lines {
+		kind, rest := splitWord(line.Text)
+		values := parseKeyValues(rest)
+
+		switch kind {
+		case "AUTH":
+			info.AuthMethods = strings.Split(values["METHODS"], ",")
+			info.CookieFile = values["COOKIEFILE"]
+		case "VERSION":
+			info.Version = values["Tor"]
+		}
+	}
+
+	return info, nil
+}
+
+// Authenticate authenticates with whatever method tor accepts: none at all,
+// `password` (if given), or the cookie file it advertises.
+//
+// A failed attempt makes tor close the connection, so no other method gets
+// tried after one fails.
+//
+func (c *Controller) Authenticate(ctx context.Context, password string) error {
+	info, err := c.ProtocolInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("protocol info: %w", err)
+	}
+
+	methods := map[string]bool{}
+	for _, method := range info.AuthMethods {
+		methods[method] = true
+	}
+
+	switch {
+	case methods[AuthMethodNull]:
+		if _, err := c.Command(ctx, "AUTHENTICATE"); err != nil {
+			return fmt.Errorf("authenticate: %w", err)
+		}
+
+		return nil
+	case methods[AuthMethodHashedPassword] && password != "":
+		if err := c.AuthenticatePassword(ctx, password); err != nil {
+			return fmt.Errorf("authenticate password: %w", err)
+		}
+
+		return nil
+	case methods[AuthMethodCookie] && info.CookieFile != "":
+		if err := c.AuthenticateCookie(ctx, info.CookieFile); err != nil {
+			return fmt.Errorf("authenticate cookie: %w", err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no supported auth method among %v", info.AuthMethods)
+}
+
+// AuthenticateCookie authenticates with the contents of the cookie file
+// that tor writes when configured with `CookieAuthentication 1`.
+//
+func (c *Controller) AuthenticateCookie(ctx context.Context, path string) error {
+	cookie, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read cookie '%s': %w", path, err)
+	}
+
+	if _, err := c.Command(ctx, "AUTHENTICATE "+hex.EncodeToString(cookie)); err != nil {
+		return fmt.Errorf("authenticate: %w", err)
+	}
+
+	return nil
+}
+
+// AuthenticatePassword authenticates with the password whose hash tor is
+// configured with (`HashedControlPassword`).
+//
+func (c *Controller) AuthenticatePassword(ctx context.Context, password string) error {
+	if _, err := c.Command(ctx, "AUTHENTICATE "+quote(password)); err != nil {
+		return fmt.Errorf("authenticate: %w", err)
+	}
+
+	return nil
+}
+
+// GetInfo retrieves the values of a set of keys (e.g., `network-liveness`,
+// `status/circuit-established`, `traffic/read`).
+//
+func (c *Controller) GetInfo(ctx context.Context, keys ...string) (map[string]string, error) {
+	reply, err := c.Command(ctx, "GETINFO "+strings.Join(keys, " "))
+	if err != nil {
+		return nil, fmt.Errorf("getinfo: %w", err)
+	}
+
+	values := map[string]string{}
+
+	for _, line := range reply.Lines {
+		idx := strings.Index(line.Text, "=")
+		if idx < 0 {
+			continue
+		}
+
+		key, value := line.Text[:idx], line.Text[idx+1:]
+		if value == "" {
+			value = line.Data
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// Signal sends a signal (e.g., SignalNewNym) to tor.
+//
+func (c *Controller) Signal(ctx context.Context, signal string) error {
+	if _, err := c.Command(ctx, "SIGNAL "+signal); err != nil {
+		return fmt.Errorf("signal: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe makes tor send the given events (e.g., `CIRC`, `HS_DESC`),
+// delivered through Events. Each call replaces the previous subscriptions,
+// with none making tor stop sending them.
+//
+func (c *Controller) Subscribe(ctx context.Context, events ...string) error {
+	if _, err := c.Command(ctx, strings.TrimSpace("SETEVENTS "+strings.Join(events, " "))); err != nil {
+		return fmt.Errorf("setevents: %w", err)
+	}
+
+	return nil
+}
+
+// OnionPort maps a port of an onion service to a target (`host:port`, or
+// just a port on localhost). With no target, the same port on localhost is
+// used.
+//
+type OnionPort struct {
+	Virtual int
+	Target  string
+}
+
+// AddOnionRequest describes an onion service to be added.
+//
+type AddOnionRequest struct {
+	// Key is either OnionKeyNew or an existing key (see ControlKey).
+	//
+	Key   string
+	Ports []OnionPort
+	Flags []string
+}
+
+// Onion is an onion service added through the control port.
+//
+type Onion struct {
+	ServiceID string
+
+	// PrivateKey is the key that tor generated, unless OnionKeyNew
+	// wasn't used or the `DiscardPK` flag was set.
+	//
+	PrivateKey string
+}
+
+// Address is the onion address of the service.
+//
+func (o *Onion) Address() string {
+	return o.ServiceID + OnionSuffix
+}
+
+// AddOnion adds an onion service, which lasts for as long as the connection
+// does (unless the `Detach` flag is set).
+//
+func (c *Controller) AddOnion(ctx context.Context, req AddOnionRequest) (*Onion, error) {
+	if len(req.Ports) == 0 {
+		return nil, fmt.Errorf("at least one port must be specified")
+	}
+
+	key := req.Key
+	if key == "" {
+		key = OnionKeyNew
+	}
+
+	command := "ADD_ONION " + key
+
+	if len(req.Flags) > 0 {
+		command += " Flags=" + strings.Join(req.Flags, ",")
+	}
+
+	for _, port := range req.Ports {
+		command += " Port=" + strconv.Itoa(port.Virtual)
+		if port.Target != "" {
+			command += "," + port.Target
+		}
+	}
+
+	reply, err := c.Command(ctx, command)
+	if err != nil {
+		return nil, fmt.Errorf("add_onion: %w", err)
+	}
+
+	onion := &Onion{}
+
+	for _, line := range reply.Lines {
+		values := parseKeyValues(line.Text)
+
+		if id, found := values["ServiceID"]; found {
+			onion.ServiceID = id
+		}
+
+		if key, found := values["PrivateKey"]; found {
+			onion.PrivateKey = key
+		}
+	}
+
+	if onion.ServiceID == "" {
+		return nil, fmt.Errorf("add_onion: no service id in reply")
+	}
+
+	return onion, nil
+}
+
+// DelOnion removes an onion service added through AddOnion, by its service
+// id (the onion address without the `.onion` suffix).
+//
+func (c *Controller) DelOnion(ctx context.Context, serviceID string) error {
+	serviceID = strings.TrimSuffix(serviceID, OnionSuffix)
+
+	if _, err := c.Command(ctx, "DEL_ONION "+serviceID); err != nil {
+		return fmt.Errorf("del_onion: %w", err)
+	}
+
+	return nil
+}
+
+// ControlKey is the key of the credentials in the form that ADD_ONION takes,
+// so that an onion service can be added with an existing identity.
+//
+func (c Credentials) ControlKey() (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", fmt.Errorf("validate: %w", err)
+	}
+
+	secretKey, err := ParseSecretKey(c[FilenameSecretKey])
+	if err != nil {
+		return "", fmt.Errorf("parse secret key: %w", err)
+	}
+
+	return "ED25519-V3:" + base64.StdEncoding.EncodeToString(secretKey), nil
+}
+
+// readLoop reads replies off the connection for as long as it's open,
+// handing events and replies to commands over to their channels.
+//
+// Replies that no command is waiting for (e.g., those tor sends right before
+// closing the connection) are dropped, so that reading never blocks.
+//
+func (c *Controller) readLoop() {
+	reader := bufio.NewReader(c.conn)
+
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			c.err = err
+			close(c.done)
+			close(c.events)
+			return
+		}
+
+		if reply.Status != StatusEvent {
+			select {
+			case replyC := <-c.pending:
+				replyC <- reply
+			default:
+			}
+
+			continue
+		}
+
+		kind, _ := splitWord(reply.Lines[0].Text)
+
+		select {
+		case c.events <- Event{Type: kind, Reply: reply}:
+		default:
+		}
+	}
+}
+
+// readReply reads a reply, made of lines in the form `<status><sep><text>`,
+// where the separator is `-` for a line followed by more, `+` for one
+// followed by a data block (terminated by a `.` line), and ` ` for the
+// last one.
+//
+func readReply(reader *bufio.Reader) (*Reply, error) {
+	reply := &Reply{}
+
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(line) < 4 {
+			return nil, fmt.Errorf("malformed reply line '%s'", line)
+		}
+
+		status, err := strconv.Atoi(line[:3])
+		if err != nil {
+			return nil, fmt.Errorf("malformed status in '%s': %w", line, err)
+		}
+
+		replyLine := ReplyLine{
+			Status: status,
+			Text:   line[4:],
+		}
+
+		switch line[3] {
+		case ' ':
+			reply.Status = status
+			reply.Lines = append(reply.Lines, replyLine)
+			return reply, nil
+		case '-':
+		case '+':
+			data, err := readData(reader)
+			if err != nil {
+				return nil, fmt.Errorf("read data: %w", err)
+			}
+
+			replyLine.Data = data
+		default:
+			return nil, fmt.Errorf("malformed separator in '%s'", line)
+		}
+
+		reply.Lines = append(reply.Lines, replyLine)
+	}
+}
+
+// readData reads a data block up to its terminating `.` line, undoing the
+// escaping of lines starting with a `.`.
+//
+func readData(reader *bufio.Reader) (string, error) {
+	lines := []string{}
+
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return "", err
+		}
+
+		if line == "." {
+			return strings.Join(lines, "\n"), nil
+		}
+
+		lines = append(lines, strings.TrimPrefix(line, "."))
+	}
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func splitWord(s string) (string, string) {
+	idx := strings.Index(s, " ")
+	if idx < 0 {
+		return s, ""
+	}
+
+	return s[:idx], s[idx+1:]
+}
+
+// parseKeyValues parses space-separated `key=value` pairs, with values
+// possibly being quoted strings.
+//
+func parseKeyValues(s string) map[string]string {
+	values := map[string]string{}
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		idx := strings.IndexAny(s, "= ")
+		if idx < 0 || s[idx] == ' ' {
+			_, s = splitWord(s)
+			continue
+		}
+
+		key := s[:idx]
+		s = s[idx+1:]
+
+		if !strings.HasPrefix(s, `"`) {
+			values[key], s = splitWord(s)
+			continue
+		}
+
+		var (
+			value   strings.Builder
+			escaped bool
+			i       int
+		)
+
+		for i = 1; i < len(s); i++ {
+			if escaped {
+				value.WriteByte(s[i])
+				escaped = false
+				continue
+			}
+
+			if s[i] == '\\' {
+				escaped = true
+				continue
+			}
+
+			if s[i] == '"' {
+				break
+			}
+
+			value.WriteByte(s[i])
+		}
+
+		values[key] = value.String()
+
+		if i < len(s) {
+			s = s[i+1:]
+		} else {
+			s = ""
+		}
+	}
+
+	return values
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
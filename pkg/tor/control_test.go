@@ -0,0 +1,429 @@
+package tor
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cirocosta/monero-operator/pkg/tor/tortest"
+)
+
+func newTestController(t *testing.T, server *tortest.Server) *Controller {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := DialController(ctx, "tcp", server.Addr())
+	if err != nil {
+		t.Fatalf("dial controller: %v", err)
+	}
+
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func newTestServer(t *testing.T) *tortest.Server {
+	t.Helper()
+
+	server, err := tortest.NewServer()
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	t.Cleanup(func() { server.Close() })
+
+	return server
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	return ctx
+}
+
+func TestAuthenticate(t *testing.T) {
+	cookie := []byte{0xde, 0xad, 0xbe, 0xef}
+	cookieFile := filepath.Join(t.TempDir(), "control_auth_cookie")
+	if err := os.WriteFile(cookieFile, cookie, 0600); err != nil {
+		t.Fatalf("write cookie: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		methods  string
+		password string
+		expected string
+	}{
+		{
+			name:     "null",
+			methods:  "NULL",
+			expected: "AUTHENTICATE",
+		},
+		{
+			name:     "cookie",
+			methods:  "COOKIE,SAFECOOKIE",
+			expected: "AUTHENTICATE " + hex.EncodeToString(cookie),
+		},
+		{
+			name:     "password",
+			methods:  "COOKIE,HASHEDPASSWORD",
+			password: `s3cr"et\`,
+			expected: `AUTHENTICATE "s3cr\"et\\"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t)
+			server.Handle("PROTOCOLINFO", func(string) []string {
+				return tortest.ProtocolInfo(tc.methods, cookieFile)
+			})
+
+			c := newTestController(t, server)
+			if err := c.Authenticate(testContext(t), tc.password); err != nil {
+				t.Fatalf("authenticate: %v", err)
+			}
+
+			commands := server.Commands()
+			if commands[len(commands)-1] != tc.expected {
+				t.Fatalf("expected '%s', got '%s'", tc.expected, commands[len(commands)-1])
+			}
+		})
+	}
+}
+
+func TestAuthenticateNoSupportedMethod(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("PROTOCOLINFO", func(string) []string {
+		return tortest.ProtocolInfo("HASHEDPASSWORD", "")
+	})
+
+	c := newTestController(t, server)
+	if err := c.Authenticate(testContext(t), ""); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestProtocolInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("PROTOCOLINFO", func(string) []string {
+		return tortest.ProtocolInfo("COOKIE,HASHEDPASSWORD", `/var/lib/tor/control "auth" cookie`)
+	})
+
+	c := newTestController(t, server)
+
+	info, err := c.ProtocolInfo(testContext(t))
+	if err != nil {
+		t.Fatalf("protocol info: %v", err)
+	}
+
+	expected := &ProtocolInfo{
+		AuthMethods: []string{"COOKIE", "HASHEDPASSWORD"},
+		CookieFile:  `/var/lib/tor/control "auth" cookie`,
+		Version:     "0.4.5.7",
+	}
+
+	if !reflect.DeepEqual(info, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, info)
+	}
+}
+
+func TestGetInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("GETINFO", func(args string) []string {
+		return []string{
+			"250-network-liveness=up",
+			"250-status/circuit-established=1",
+			"250+config-text=",
+			"SOCKSPort 9050",
+			"..hidden",
+			".",
+			"250 OK",
+		}
+	})
+
+	c := newTestController(t, server)
+
+	info, err := c.GetInfo(testContext(t), "network-liveness", "status/circuit-established", "config-text")
+	if err != nil {
+		t.Fatalf("get info: %v", err)
+	}
+
+	expected := map[string]string{
+		"network-liveness":           "up",
+		"status/circuit-established": "1",
+		"config-text":                "SOCKSPort 9050\n.hidden",
+	}
+
+	if !reflect.DeepEqual(info, expected) {
+		t.Fatalf("expected %v, got %v", expected, info)
+	}
+
+	commands := server.Commands()
+	if commands[0] != "GETINFO network-liveness status/circuit-established config-text" {
+		t.Fatalf("unexpected command '%s'", commands[0])
+	}
+}
+
+func TestControlError(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("SIGNAL", func(args string) []string {
+		return []string{`552 Unrecognized signal code "` + args + `"`}
+	})
+
+	c := newTestController(t, server)
+	ctx := testContext(t)
+
+	err := c.Signal(ctx, "BOGUS")
+
+	var controlErr *ControlError
+	if !errors.As(err, &controlErr) {
+		t.Fatalf("expected control error, got %v", err)
+	}
+
+	if controlErr.Status != 552 || !strings.Contains(controlErr.Message, "BOGUS") {
+		t.Fatalf("unexpected control error %+v", controlErr)
+	}
+
+	// the connection stays usable after an error reply.
+	//
+	if _, err := c.Command(ctx, "UNKNOWN"); !errors.As(err, &controlErr) || controlErr.Status != 510 {
+		t.Fatalf("expected 510, got %v", err)
+	}
+}
+
+func TestSignal(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("SIGNAL", func(string) []string {
+		return []string{"250 OK"}
+	})
+
+	c := newTestController(t, server)
+	if err := c.Signal(testContext(t), SignalNewNym); err != nil {
+		t.Fatalf("signal: %v", err)
+	}
+
+	if commands := server.Commands(); commands[0] != "SIGNAL NEWNYM" {
+		t.Fatalf("unexpected command '%s'", commands[0])
+	}
+}
+
+func TestAddDelOnion(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("ADD_ONION", func(string) []string {
+		return []string{
+			"250-ServiceID=25njqamcweflpvkl73j4szahhihoc4xt3ktcgjnpaingr5yhkenl5sid",
+			"250-PrivateKey=ED25519-V3:c2VjcmV0",
+			"250 OK",
+		}
+	})
+	server.Handle("DEL_ONION", func(string) []string {
+		return []string{"250 OK"}
+	})
+
+	c := newTestController(t, server)
+	ctx := testContext(t)
+
+	onion, err := c.AddOnion(ctx, AddOnionRequest{
+		Ports: []OnionPort{
+			{Virtual: 18083, Target: "127.0.0.1:18083"},
+			{Virtual: 80},
+		},
+		Flags: []string{OnionFlagDetach},
+	})
+	if err != nil {
+		t.Fatalf("add onion: %v", err)
+	}
+
+	expected := &Onion{
+		ServiceID:  "25njqamcweflpvkl73j4szahhihoc4xt3ktcgjnpaingr5yhkenl5sid",
+		PrivateKey: "ED25519-V3:c2VjcmV0",
+	}
+
+	if !reflect.DeepEqual(onion, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, onion)
+	}
+
+	if err := VerifyOnionAddress(onion.Address()); err != nil {
+		t.Fatalf("verify onion address: %v", err)
+	}
+
+	if err := c.DelOnion(ctx, onion.Address()); err != nil {
+		t.Fatalf("del onion: %v", err)
+	}
+
+	commands := server.Commands()
+	if commands[0] != "ADD_ONION NEW:ED25519-V3 Flags=Detach Port=18083,127.0.0.1:18083 Port=80" {
+		t.Fatalf("unexpected command '%s'", commands[0])
+	}
+
+	if commands[1] != "DEL_ONION "+expected.ServiceID {
+		t.Fatalf("unexpected command '%s'", commands[1])
+	}
+}
+
+func TestAddOnionWithExistingKey(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("ADD_ONION", func(string) []string {
+		return []string{
+			"250-ServiceID=25njqamcweflpvkl73j4szahhihoc4xt3ktcgjnpaingr5yhkenl5sid",
+			"250 OK",
+		}
+	})
+
+	key, err := readFixture(t).ControlKey()
+	if err != nil {
+		t.Fatalf("control key: %v", err)
+	}
+
+	c := newTestController(t, server)
+	if _, err := c.AddOnion(testContext(t), AddOnionRequest{
+		Key:   key,
+		Ports: []OnionPort{{Virtual: 80}},
+	}); err != nil {
+		t.Fatalf("add onion: %v", err)
+	}
+
+	if commands := server.Commands(); commands[0] != "ADD_ONION "+key+" Port=80" {
+		t.Fatalf("unexpected command '%s'", commands[0])
+	}
+}
+
+func TestEventsInterleaved(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("SETEVENTS", func(string) []string {
+		return []string{"250 OK"}
+	})
+	server.Handle("GETINFO", func(string) []string {
+		return []string{
+			"650 CIRC 1 BUILT",
+			"650-HS_DESC UPLOADED abc UNKNOWN $hsdir",
+			"650 OK",
+			"250-network-liveness=up",
+			"250 OK",
+		}
+	})
+
+	c := newTestController(t, server)
+	ctx := testContext(t)
+
+	if err := c.Subscribe(ctx, "CIRC", "HS_DESC"); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	info, err := c.GetInfo(ctx, "network-liveness")
+	if err != nil {
+		t.Fatalf("get info: %v", err)
+	}
+
+	if info["network-liveness"] != "up" {
+		t.Fatalf("unexpected info %v", info)
+	}
+
+	server.Send("650 STATUS_GENERAL NOTICE CLOCK_JUMPED")
+
+	for _, expected := range []string{"CIRC", "HS_DESC", "STATUS_GENERAL"} {
+		select {
+		case event := <-c.Events():
+			if event.Type != expected {
+				t.Fatalf("expected %s, got %s", expected, event.Type)
+			}
+		case <-ctx.Done():
+			t.Fatalf("waiting for %s: %v", expected, ctx.Err())
+		}
+	}
+
+	if commands := server.Commands(); commands[0] != "SETEVENTS CIRC HS_DESC" {
+		t.Fatalf("unexpected command '%s'", commands[0])
+	}
+}
+
+func TestUnsolicitedReplyDoesNotBlock(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("SIGNAL", func(string) []string {
+		return []string{"250 OK"}
+	})
+
+	c := newTestController(t, server)
+	ctx := testContext(t)
+
+	// a reply with no command waiting for it, followed by an event,
+	// which must still get through.
+	//
+	server.Send("514 Authentication required.", "650 CIRC 1 BUILT")
+
+	select {
+	case event := <-c.Events():
+		if event.Type != "CIRC" {
+			t.Fatalf("unexpected event %s", event.Type)
+		}
+	case <-ctx.Done():
+		t.Fatalf("read loop blocked: %v", ctx.Err())
+	}
+
+	if err := c.Signal(ctx, SignalNewNym); err != nil {
+		t.Fatalf("signal: %v", err)
+	}
+
+	server.Close()
+
+	select {
+	case _, ok := <-c.Events():
+		if ok {
+			t.Fatalf("expected events to be closed")
+		}
+	case <-ctx.Done():
+		t.Fatalf("events not closed: %v", ctx.Err())
+	}
+
+	if err := c.Signal(ctx, SignalNewNym); err == nil {
+		t.Fatalf("expected error on closed connection")
+	}
+}
+
+func TestCommandCancellationClosesConnection(t *testing.T) {
+	server := newTestServer(t)
+	server.Handle("GETINFO", func(string) []string {
+		return nil
+	})
+
+	c := newTestController(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.GetInfo(ctx, "traffic/read"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	select {
+	case <-server.Closed():
+	case <-testContext(t).Done():
+		t.Fatalf("connection not closed")
+	}
+
+	if _, err := c.GetInfo(testContext(t), "traffic/read"); err == nil {
+		t.Fatalf("expected error on closed connection")
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	values := parseKeyValues(`METHODS=COOKIE,SAFECOOKIE COOKIEFILE="/a \"b\" \\c" FLAG Tor="0.4.5.7"`)
+
+	expected := map[string]string{
+		"METHODS":    "COOKIE,SAFECOOKIE",
+		"COOKIEFILE": `/a "b" \c`,
+		"Tor":        "0.4.5.7",
+	}
+
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+}
@@ -5,51 +5,273 @@ package tor
 import (
 	"bytes"
 	"crypto/ed25519"
+	"crypto/sha512"
 	"encoding/base32"
 	"fmt"
 	"strings"
 
+	"filippo.io/edwards25519"
 	"golang.org/x/crypto/sha3"
 )
 
 const (
 	FilenameHostname  = "hostname"
-	FilenamePublicKey = "hs_ed25519_secret_key"
-	FilenameSecretKey = "hs_ed25519_public_key"
+	FilenamePublicKey = "hs_ed25519_public_key"
+	FilenameSecretKey = "hs_ed25519_secret_key"
+
+	// SecretKeyHeader and PublicKeyHeader are the 32-byte headers that
+	// tor prefixes the keys of v3 hidden services with.
+	//
+	SecretKeyHeader = "== ed25519v1-secret: type0 ==\x00\x00\x00"
+	PublicKeyHeader = "== ed25519v1-public: type0 ==\x00\x00\x00"
+
+	// ExpandedSecretKeySize is the size of the secret keys that tor
+	// stores: the clamped scalar followed by the nonce prefix, as
+	// derived from the seed through SHA-512.
+	//
+	ExpandedSecretKeySize = 64
+
+	OnionAddressVersion = 0x03
+	OnionSuffix         = ".onion"
+
+	onionChecksumPrefix = ".onion checksum"
+	onionAddressLength  = 56
 )
 
+var onionEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Credentials holds the contents of the files that make up the
+// directory of a v3 hidden service, keyed by their filenames.
+//
 type Credentials map[string][]byte
 
+// GenerateCredentials generates a new v3 hidden service identity, with the
+// files being byte-for-byte what tor itself would've written.
+//
 func GenerateCredentials() (Credentials, error) {
 	publicKey, secretKey, err := ed25519.GenerateKey(nil)
 	if err != nil {
 		return nil, fmt.Errorf("generate key: %w", err)
 	}
 
-	onionAddress := encodePublicKey(publicKey)
+	return NewCredentials(publicKey, ExpandSecretKey(secretKey.Seed())), nil
+}
 
+// NewCredentials assembles the hidden service files for a key pair, with
+// `secretKey` in its expanded form.
+//
+func NewCredentials(publicKey ed25519.PublicKey, secretKey []byte) Credentials {
 	return Credentials{
-		FilenameSecretKey: append([]byte("== ed25519v1-secret: type0 ==\x00\x00\x00"), secretKey[:]...),
-		FilenamePublicKey: append([]byte("== ed25519v1-public: type0 ==\x00\x00\x00"), publicKey...),
-		FilenameHostname:  []byte(onionAddress + ".onion"),
-	}, nil
+		FilenameSecretKey: append([]byte(SecretKeyHeader), secretKey...),
+		FilenamePublicKey: append([]byte(PublicKeyHeader), publicKey...),
+		FilenameHostname:  []byte(OnionAddress(publicKey) + "\n"),
+	}
+}
+
+// ExpandSecretKey derives from an ed25519 seed the secret key in the form
+// that tor stores it: SHA-512 of the seed, with the first half clamped.
+//
+func ExpandSecretKey(seed []byte) []byte {
+	h := sha512.Sum512(seed)
+
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+
+	return h[:]
+}
+
+// ParseSecretKey retrieves the expanded secret key out of the contents of
+// an `hs_ed25519_secret_key` file.
+//
+func ParseSecretKey(b []byte) ([]byte, error) {
+	if !bytes.HasPrefix(b, []byte(SecretKeyHeader)) {
+		return nil, fmt.Errorf("missing secret key header")
+	}
+
+	key := b[len(SecretKeyHeader):]
+	if len(key) != ExpandedSecretKeySize {
+		return nil, fmt.Errorf("expected %d bytes of secret key, got %d",
+			ExpandedSecretKeySize, len(key),
+		)
+	}
+
+	if key[0]&7 != 0 || key[31]&192 != 64 {
+		return nil, fmt.Errorf("secret key not clamped")
+	}
+
+	return key, nil
+}
+
+// ParsePublicKey retrieves the public key out of the contents of an
+// `hs_ed25519_public_key` file.
+//
+func ParsePublicKey(b []byte) (ed25519.PublicKey, error) {
+	if !bytes.HasPrefix(b, []byte(PublicKeyHeader)) {
+		return nil, fmt.Errorf("missing public key header")
+	}
+
+	key := b[len(PublicKeyHeader):]
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected %d bytes of public key, got %d",
+			ed25519.PublicKeySize, len(key),
+		)
+	}
+
+	return ed25519.PublicKey(key), nil
+}
+
+// PublicKeyFromSecretKey derives the public key that corresponds to an
+// expanded secret key, which the standard library doesn't allow for (it only
+// takes seeds, which tor doesn't keep around).
+//
+func PublicKeyFromSecretKey(secretKey []byte) (ed25519.PublicKey, error) {
+	if len(secretKey) != ExpandedSecretKeySize {
+		return nil, fmt.Errorf("expected %d bytes of secret key, got %d",
+			ExpandedSecretKeySize, len(secretKey),
+		)
+	}
+
+	var (
+		scalar = new(edwards25519.Scalar).SetBytesWithClamping(secretKey[:32])
+		point  = new(edwards25519.Point).ScalarBaseMult(scalar)
+	)
+
+	return ed25519.PublicKey(point.Bytes()), nil
+}
+
+// OnionAddress encodes a public key as a v3 onion address (including the
+// `.onion` suffix).
+//
+func OnionAddress(publicKey ed25519.PublicKey) string {
+	return encodePublicKey(publicKey) + OnionSuffix
+}
+
+// ParseOnionAddress retrieves the public key out of a v3 onion address
+// (with or without the `.onion` suffix), verifying its version and
+// checksum.
+//
+func ParseOnionAddress(address string) (ed25519.PublicKey, error) {
+	encoded := strings.TrimSuffix(strings.TrimSpace(address), OnionSuffix)
+	if len(encoded) != onionAddressLength {
+		return nil, fmt.Errorf("expected %d characters, got %d",
+			onionAddressLength, len(encoded),
+		)
+	}
+
+	b, err := onionEncoding.DecodeString(strings.ToUpper(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	var (
+		publicKey = ed25519.PublicKey(b[:ed25519.PublicKeySize])
+		checksum  = b[ed25519.PublicKeySize : ed25519.PublicKeySize+2]
+		version   = b[ed25519.PublicKeySize+2]
+	)
+
+	if version != OnionAddressVersion {
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+
+	expected := onionChecksum(publicKey)
+	if !bytes.Equal(checksum, expected[:2]) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+
+	return publicKey, nil
+}
+
+// VerifyOnionAddress checks that `address` is a well-formed v3 onion
+// address.
+//
+func VerifyOnionAddress(address string) error {
+	_, err := ParseOnionAddress(address)
+	return err
+}
+
+// Validate checks that the credentials are well-formed and consistent: that
+// the secret key is a valid expanded key and that the public key and the
+// hostname, if present (tor derives them from the secret key otherwise),
+// match it.
+//
+func (c Credentials) Validate() error {
+	_, err := c.OnionAddress()
+	return err
+}
+
+// OnionAddress validates the credentials, retrieving the onion address that
+// they make up.
+//
+func (c Credentials) OnionAddress() (string, error) {
+	b, found := c[FilenameSecretKey]
+	if !found {
+		return "", fmt.Errorf("missing %s", FilenameSecretKey)
+	}
+
+	secretKey, err := ParseSecretKey(b)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", FilenameSecretKey, err)
+	}
+
+	derived, err := PublicKeyFromSecretKey(secretKey)
+	if err != nil {
+		return "", fmt.Errorf("derive public key: %w", err)
+	}
+
+	if b, found := c[FilenamePublicKey]; found {
+		publicKey, err := ParsePublicKey(b)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", FilenamePublicKey, err)
+		}
+
+		if !bytes.Equal(derived, publicKey) {
+			return "", fmt.Errorf("%s doesn't match %s", FilenamePublicKey, FilenameSecretKey)
+		}
+	}
+
+	address := OnionAddress(derived)
+
+	if _, found := c[FilenameHostname]; found {
+		hostname := c.Hostname()
+		if err := VerifyOnionAddress(hostname); err != nil {
+			return "", fmt.Errorf("%s: %w", FilenameHostname, err)
+		}
+
+		if hostname != address {
+			return "", fmt.Errorf("%s doesn't match %s", FilenameHostname, FilenameSecretKey)
+		}
+	}
+
+	return address, nil
+}
+
+// Hostname is the onion address in the hostname file, without the trailing
+// newline.
+//
+func (c Credentials) Hostname() string {
+	return strings.TrimSpace(string(c[FilenameHostname]))
+}
+
+func onionChecksum(publicKey ed25519.PublicKey) [32]byte {
+	var b bytes.Buffer
+
+	b.Write([]byte(onionChecksumPrefix))
+	b.Write([]byte(publicKey))
+	b.Write([]byte{OnionAddressVersion})
+
+	return sha3.Sum256(b.Bytes())
 }
 
 func encodePublicKey(publicKey ed25519.PublicKey) string {
 	var (
-		checksumBytes     bytes.Buffer
+		checksum          = onionChecksum(publicKey)
 		onionAddressBytes bytes.Buffer
 	)
 
-	checksumBytes.Write([]byte(".onion checksum"))
-	checksumBytes.Write([]byte(publicKey))
-	checksumBytes.Write([]byte{0x03})
-	checksum := sha3.Sum256(checksumBytes.Bytes())
-
 	onionAddressBytes.Write([]byte(publicKey))
 	onionAddressBytes.Write(checksum[:2])
-	onionAddressBytes.Write([]byte{0x03})
-	onionAddress := base32.StdEncoding.EncodeToString(onionAddressBytes.Bytes())
+	onionAddressBytes.Write([]byte{OnionAddressVersion})
 
-	return strings.ToLower(onionAddress)
+	return strings.ToLower(onionEncoding.EncodeToString(onionAddressBytes.Bytes()))
 }
@@ -0,0 +1,263 @@
+package tor
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixtureDir holds a hidden service directory for the key pair of the first
+// test vector of RFC 8032, laid out as tor's hs_ed25519_* files are (see
+// rend-spec-v3 and tor's ed25519 key files) but written out by hand, not by
+// tor itself (see its README): it pins down the encoding against the RFC's
+// keys, while knownAddresses cover what tor actually produces.
+//
+const fixtureDir = "testdata/rfc8032"
+
+// onion addresses of well-known services, as published by them (and so
+// generated by tor), for checking addresses (checksum and version) against
+// tor's own encoding.
+//
+var knownAddresses = []string{
+	"duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion",
+	"2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion",
+}
+
+func readFixture(t *testing.T) Credentials {
+	t.Helper()
+
+	creds := Credentials{}
+	for _, filename := range []string{FilenameSecretKey, FilenamePublicKey, FilenameHostname} {
+		b, err := os.ReadFile(filepath.Join(fixtureDir, filename))
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+
+		creds[filename] = b
+	}
+
+	return creds
+}
+
+func TestNewCredentialsMatchesFixture(t *testing.T) {
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	key := ed25519.NewKeyFromSeed(seed)
+
+	creds := NewCredentials(key.Public().(ed25519.PublicKey), ExpandSecretKey(seed))
+	fixture := readFixture(t)
+
+	for filename, expected := range fixture {
+		if !bytes.Equal(creds[filename], expected) {
+			t.Errorf("%s: expected %x, got %x", filename, expected, creds[filename])
+		}
+	}
+}
+
+func TestFixtureRoundTrip(t *testing.T) {
+	fixture := readFixture(t)
+
+	address, err := fixture.OnionAddress()
+	if err != nil {
+		t.Fatalf("onion address: %v", err)
+	}
+
+	if address != fixture.Hostname() {
+		t.Fatalf("expected %s, got %s", fixture.Hostname(), address)
+	}
+
+	secretKey, err := ParseSecretKey(fixture[FilenameSecretKey])
+	if err != nil {
+		t.Fatalf("parse secret key: %v", err)
+	}
+
+	publicKey, err := ParsePublicKey(fixture[FilenamePublicKey])
+	if err != nil {
+		t.Fatalf("parse public key: %v", err)
+	}
+
+	derived, err := PublicKeyFromSecretKey(secretKey)
+	if err != nil {
+		t.Fatalf("public key from secret key: %v", err)
+	}
+
+	if !bytes.Equal(derived, publicKey) {
+		t.Fatalf("expected %x, got %x", publicKey, derived)
+	}
+
+	parsed, err := ParseOnionAddress(address)
+	if err != nil {
+		t.Fatalf("parse onion address: %v", err)
+	}
+
+	if !bytes.Equal(parsed, publicKey) {
+		t.Fatalf("expected %x, got %x", publicKey, parsed)
+	}
+}
+
+func TestGenerateCredentialsRoundTrip(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		creds, err := GenerateCredentials()
+		if err != nil {
+			t.Fatalf("generate credentials: %v", err)
+		}
+
+		address, err := creds.OnionAddress()
+		if err != nil {
+			t.Fatalf("onion address: %v", err)
+		}
+
+		if address != creds.Hostname() {
+			t.Fatalf("expected %s, got %s", creds.Hostname(), address)
+		}
+
+		if err := VerifyOnionAddress(address); err != nil {
+			t.Fatalf("verify onion address: %v", err)
+		}
+
+		// tor derives the public key and the hostname from the
+		// secret key alone.
+		//
+		if err := (Credentials{FilenameSecretKey: creds[FilenameSecretKey]}).Validate(); err != nil {
+			t.Fatalf("validate secret key only: %v", err)
+		}
+	}
+}
+
+func TestVerifyOnionAddressKnown(t *testing.T) {
+	for _, address := range knownAddresses {
+		if err := VerifyOnionAddress(address); err != nil {
+			t.Errorf("%s: %v", address, err)
+		}
+
+		if err := VerifyOnionAddress(strings.TrimSuffix(address, OnionSuffix)); err != nil {
+			t.Errorf("%s without suffix: %v", address, err)
+		}
+	}
+}
+
+func TestParseOnionAddressRejects(t *testing.T) {
+	publicKey, err := ParsePublicKey(readFixture(t)[FilenamePublicKey])
+	if err != nil {
+		t.Fatalf("parse public key: %v", err)
+	}
+
+	encode := func(checksum []byte, version byte) string {
+		b := append(append(append([]byte{}, publicKey...), checksum...), version)
+		return strings.ToLower(onionEncoding.EncodeToString(b)) + OnionSuffix
+	}
+
+	valid := onionChecksum(publicKey)
+
+	for _, tc := range []struct {
+		name    string
+		address string
+		err     string
+	}{
+		{
+			name:    "bad checksum",
+			address: encode([]byte{valid[0] ^ 0xff, valid[1]}, OnionAddressVersion),
+			err:     "checksum mismatch",
+		},
+		{
+			name:    "bad version",
+			address: encode(valid[:2], 0x02),
+			err:     "unsupported version",
+		},
+		{
+			name:    "too short",
+			address: "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzcza.onion",
+			err:     "expected 56 characters",
+		},
+		{
+			name:    "not base32",
+			address: "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzcza1.onion",
+			err:     "decode",
+		},
+		{
+			name:    "tampered",
+			address: "euckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion",
+			err:     "checksum mismatch",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseOnionAddress(tc.address)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing '%s', got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestCredentialsValidateRejects(t *testing.T) {
+	other, err := GenerateCredentials()
+	if err != nil {
+		t.Fatalf("generate credentials: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(c Credentials)
+		err    string
+	}{
+		{
+			name:   "missing secret key",
+			mutate: func(c Credentials) { delete(c, FilenameSecretKey) },
+			err:    "missing",
+		},
+		{
+			name: "bad secret key header",
+			mutate: func(c Credentials) {
+				c[FilenameSecretKey] = append([]byte("== ed25519v1-secret: type1 ==\x00\x00\x00"),
+					c[FilenameSecretKey][len(SecretKeyHeader):]...)
+			},
+			err: "missing secret key header",
+		},
+		{
+			name:   "truncated secret key",
+			mutate: func(c Credentials) { c[FilenameSecretKey] = c[FilenameSecretKey][:80] },
+			err:    "expected 64 bytes",
+		},
+		{
+			name:   "mismatched public key",
+			mutate: func(c Credentials) { c[FilenamePublicKey] = other[FilenamePublicKey] },
+			err:    "doesn't match",
+		},
+		{
+			name:   "mismatched hostname",
+			mutate: func(c Credentials) { c[FilenameHostname] = other[FilenameHostname] },
+			err:    "doesn't match",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			creds := readFixture(t)
+			tc.mutate(creds)
+
+			err := creds.Validate()
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing '%s', got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestScalarBaseMultMatchesStdlib(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		publicKey, secretKey, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+
+		derived, err := PublicKeyFromSecretKey(ExpandSecretKey(secretKey.Seed()))
+		if err != nil {
+			t.Fatalf("public key from secret key: %v", err)
+		}
+
+		if !bytes.Equal(derived, publicKey) {
+			t.Fatalf("expected %x, got %x", publicKey, derived)
+		}
+	}
+}
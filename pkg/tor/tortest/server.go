@@ -0,0 +1,200 @@
+// Package tortest provides a fake tor control port, for testing code that
+// drives tor through it.
+//
+package tortest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+)
+
+// HandlerFunc replies to a command (given its arguments) with raw reply
+// lines, without the trailing CRLF (e.g., `250-key=value`, `250 OK`).
+//
+// Returning no lines leaves the command without a reply.
+//
+type HandlerFunc func(args string) []string
+
+// Server is a fake tor control port, replying to each command with what the
+// handler registered for its keyword returns.
+//
+type Server struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	conns    []net.Conn
+	commands []string
+	closed   chan struct{}
+}
+
+// NewServer starts a fake control port listening on a random local port,
+// replying to PROTOCOLINFO (advertising no authentication) and
+// AUTHENTICATE with success, and to unknown commands with 510.
+//
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	s := &Server{
+		listener: listener,
+		closed:   make(chan struct{}),
+		handlers: map[string]HandlerFunc{
+			"PROTOCOLINFO": func(string) []string {
+				return ProtocolInfo("NULL", "")
+			},
+			"AUTHENTICATE": func(string) []string {
+				return []string{"250 OK"}
+			},
+		},
+	}
+
+	go s.accept()
+
+	return s, nil
+}
+
+// Addr is the address that the server listens on.
+//
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Handle registers the handler for the commands with a given keyword (e.g.,
+// `GETINFO`).
+//
+func (s *Server) Handle(keyword string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[keyword] = handler
+}
+
+// Commands retrieves the commands received so far, across all connections.
+//
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.commands...)
+}
+
+// Send writes raw lines (e.g., `650 HS_DESC ...` events) to every
+// connection, regardless of any command.
+//
+func (s *Server) Send(lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		writeLines(conn, lines)
+	}
+}
+
+// Closed is closed once the server notices that a client closed its
+// connection.
+//
+func (s *Server) Closed() <-chan struct{} {
+	return s.closed
+}
+
+// Close stops listening and closes every connection.
+//
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+
+	return s.listener.Close()
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				s.closeOnce()
+			}
+
+			return
+		}
+
+		command := strings.TrimRight(line, "\r\n")
+		keyword, args := command, ""
+		if idx := strings.Index(command, " "); idx >= 0 {
+			keyword, args = command[:idx], command[idx+1:]
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, command)
+		handler, found := s.handlers[keyword]
+		s.mu.Unlock()
+
+		if !found {
+			writeLines(conn, []string{fmt.Sprintf(`510 Unrecognized command "%s"`, keyword)})
+			continue
+		}
+
+		writeLines(conn, handler(args))
+	}
+}
+
+func (s *Server) closeOnce() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+}
+
+func writeLines(w io.Writer, lines []string) {
+	for _, line := range lines {
+		fmt.Fprint(w, line+"\r\n")
+	}
+}
+
+// ProtocolInfo is the reply to PROTOCOLINFO advertising a set of auth methods
+// (e.g., `COOKIE,HASHEDPASSWORD`) and, if not empty, a cookie file.
+//
+func ProtocolInfo(methods, cookieFile string) []string {
+	auth := "250-AUTH METHODS=" + methods
+	if cookieFile != "" {
+		auth += fmt.Sprintf(` COOKIEFILE="%s"`, strings.ReplaceAll(cookieFile, `"`, `\"`))
+	}
+
+	return []string{
+		"250-PROTOCOLINFO 1",
+		auth,
+		`250-VERSION Tor="0.4.5.7"`,
+		"250 OK",
+	}
+}
@@ -0,0 +1,193 @@
+package tor
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// MaxPrefixLength is the longest prefix that can be searched for:
+	// past it, characters of the address depend on the checksum rather
+	// than on the public key alone.
+	//
+	MaxPrefixLength = 51
+
+	onionAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+
+	// how many keys each worker goes through between checking whether
+	// it should stop.
+	//
+	vanityBatchSize = 256
+)
+
+// ValidatePrefix checks that an onion address could possibly start with
+// `prefix`.
+//
+func ValidatePrefix(prefix string) error {
+	if prefix == "" {
+		return fmt.Errorf("empty prefix")
+	}
+
+	if len(prefix) > MaxPrefixLength {
+		return fmt.Errorf("prefix longer than %d characters", MaxPrefixLength)
+	}
+
+	for _, c := range prefix {
+		if !strings.ContainsRune(onionAlphabet, c) {
+			return fmt.Errorf("'%c' not in the onion address alphabet (a-z, 2-7)", c)
+		}
+	}
+
+	return nil
+}
+
+// ExpectedAttempts is the number of keys that, on average, need to be
+// generated to find an address starting with `prefix`.
+//
+func ExpectedAttempts(prefix string) float64 {
+	return math.Pow(32, float64(len(prefix)))
+}
+
+// ExpectedSearchTime is how long, on average, finding an address starting
+// with `prefix` takes when going through `keysPerSecond` keys.
+//
+func ExpectedSearchTime(prefix string, keysPerSecond float64) time.Duration {
+	if keysPerSecond <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	seconds := ExpectedAttempts(prefix) / keysPerSecond
+	if seconds >= float64(math.MaxInt64)/float64(time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// MeasureKeyRate measures how many keys per second `workers` goroutines
+// (all CPUs, if not positive) get to go through, searching for `duration`.
+//
+func MeasureKeyRate(ctx context.Context, duration time.Duration, workers int) float64 {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	start := time.Now()
+
+	attempts, _, _ := search(ctx, nil, workers)
+
+	return float64(attempts) / time.Since(start).Seconds()
+}
+
+// GenerateVanityCredentials searches, across `workers` goroutines (all CPUs,
+// if not positive), for a hidden service identity whose onion address
+// starts with `prefix`, until one is found or the context is done.
+//
+// Each character of the prefix makes the search 32 times longer (see
+// ExpectedSearchTime).
+//
+func GenerateVanityCredentials(
+	ctx context.Context,
+	prefix string,
+	workers int,
+) (Credentials, error) {
+	if err := ValidatePrefix(prefix); err != nil {
+		return nil, fmt.Errorf("validate prefix: %w", err)
+	}
+
+	_, seed, err := search(ctx, []byte(strings.ToUpper(prefix)), workers)
+	if err != nil {
+		return nil, fmt.Errorf("search '%s': %w", prefix, err)
+	}
+
+	key := ed25519.NewKeyFromSeed(seed)
+
+	return NewCredentials(key.Public().(ed25519.PublicKey), ExpandSecretKey(seed)), nil
+}
+
+// search goes through keys until one whose (uppercase) encoding starts
+// with `prefix` is found, or the context is done, retrieving the number of
+// keys that were generated and the seed of the match (or why there's none:
+// the context being done, or a worker failing to get a random seed).
+//
+// Each worker starts off a random seed, incrementing it from there, which
+// is as good as picking new random seeds given that they're hashed.
+//
+func search(ctx context.Context, prefix []byte, workers int) (uint64, []byte, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts uint64
+		result   []byte
+		err      error
+		once     sync.Once
+		wg       sync.WaitGroup
+	)
+
+	stop := func(seed []byte, reason error) {
+		once.Do(func() {
+			result, err = seed, reason
+			cancel()
+		})
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			seed := make([]byte, ed25519.SeedSize)
+			if _, err := rand.Read(seed); err != nil {
+				stop(nil, fmt.Errorf("random seed: %w", err))
+				return
+			}
+
+			encoded := make([]byte, onionEncoding.EncodedLen(ed25519.PublicKeySize))
+
+			for ctx.Err() == nil {
+				for j := 0; j < vanityBatchSize; j++ {
+					counter := binary.LittleEndian.Uint64(seed)
+					binary.LittleEndian.PutUint64(seed, counter+1)
+
+					key := ed25519.NewKeyFromSeed(seed)
+					if prefix == nil {
+						continue
+					}
+
+					onionEncoding.Encode(encoded, key[ed25519.SeedSize:])
+					if string(encoded[:len(prefix)]) != string(prefix) {
+						continue
+					}
+
+					stop(append([]byte{}, seed...), nil)
+
+					break
+				}
+
+				atomic.AddUint64(&attempts, vanityBatchSize)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if result == nil && err == nil {
+		err = ctx.Err()
+	}
+
+	return atomic.LoadUint64(&attempts), result, err
+}
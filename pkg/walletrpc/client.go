@@ -0,0 +1,228 @@
+package walletrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cirocosta/go-monero/pkg/daemonrpc"
+)
+
+// Client is a thin client for the JSON-RPC interface of monero-wallet-rpc.
+//
+type Client struct {
+	rpc *daemonrpc.Client
+}
+
+type ClientOption func(c *http.Client)
+
+// WithCredentials makes the client authenticate against a wallet-rpc
+// started with `--rpc-login`.
+//
+func WithCredentials(username, password string) ClientOption {
+	return func(c *http.Client) {
+		c.Transport = NewDigestTransport(username, password)
+	}
+}
+
+func NewClient(address string, opts ...ClientOption) (*Client, error) {
+	httpClient := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(httpClient)
+	}
+
+	rpc, err := daemonrpc.NewClient(address, daemonrpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("new client '%s': %w", address, err)
+	}
+
+	return &Client{rpc: rpc}, nil
+}
+
+// ErrorCodeNotOpen is the code of the error that wallet-rpc replies with
+// when there's no wallet opened.
+//
+const ErrorCodeNotOpen = -13
+
+// IsNotOpen tells whether an error is wallet-rpc complaining that there's no
+// wallet opened.
+//
+func IsNotOpen(err error) bool {
+	return err != nil && strings.Contains(err.Error(), fmt.Sprintf("code=%d ", ErrorCodeNotOpen))
+}
+
+// IsFileNotFound tells whether an error is wallet-rpc failing to open a
+// wallet because there's no such file in the wallet directory.
+//
+// wallet-rpc replies to any failure to open a wallet with the same generic
+// error code, so the message (from wallet2's `file_not_found`) is what tells
+// a missing wallet apart from, e.g., a wrong password.
+//
+func IsFileNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "file not found")
+}
+
+type GetVersionResult struct {
+	Version uint32 `json:"version"`
+}
+
+// GetVersion retrieves the version of the RPC interface - useful for
+// verifying that wallet-rpc is up even when no wallet has been opened.
+//
+func (c *Client) GetVersion(ctx context.Context) (*GetVersionResult, error) {
+	resp := &GetVersionResult{}
+	if err := c.rpc.JsonRPC(ctx, "get_version", nil, resp); err != nil {
+		return nil, fmt.Errorf("get_version: %w", err)
+	}
+
+	return resp, nil
+}
+
+type GetHeightResult struct {
+	Height uint64 `json:"height"`
+}
+
+// GetHeight retrieves the blockchain height as seen by the currently opened
+// wallet.
+//
+func (c *Client) GetHeight(ctx context.Context) (*GetHeightResult, error) {
+	resp := &GetHeightResult{}
+	if err := c.rpc.JsonRPC(ctx, "get_height", nil, resp); err != nil {
+		return nil, fmt.Errorf("get_height: %w", err)
+	}
+
+	return resp, nil
+}
+
+type OpenWalletRequestParameters struct {
+	Filename string `json:"filename"`
+	Password string `json:"password"`
+}
+
+// OpenWallet opens a wallet previously created in the wallet directory.
+//
+func (c *Client) OpenWallet(ctx context.Context, filename, password string) error {
+	if err := c.rpc.JsonRPC(ctx, "open_wallet", &OpenWalletRequestParameters{
+		Filename: filename,
+		Password: password,
+	}, &struct{}{}); err != nil {
+		return fmt.Errorf("open_wallet: %w", err)
+	}
+
+	return nil
+}
+
+type GenerateFromKeysRequestParameters struct {
+	RestoreHeight   uint64 `json:"restore_height"`
+	Filename        string `json:"filename"`
+	Address         string `json:"address"`
+	ViewKey         string `json:"viewkey"`
+	Password        string `json:"password"`
+	AutosaveCurrent bool   `json:"autosave_current"`
+}
+
+type GenerateFromKeysResult struct {
+	Address string `json:"address"`
+	Info    string `json:"info"`
+}
+
+// GenerateFromKeys restores a wallet from its keys - without a spend key, a
+// view-only wallet is created (and opened).
+//
+func (c *Client) GenerateFromKeys(
+	ctx context.Context, params *GenerateFromKeysRequestParameters,
+) (*GenerateFromKeysResult, error) {
+	resp := &GenerateFromKeysResult{}
+	if err := c.rpc.JsonRPC(ctx, "generate_from_keys", params, resp); err != nil {
+		return nil, fmt.Errorf("generate_from_keys: %w", err)
+	}
+
+	return resp, nil
+}
+
+type RefreshResult struct {
+	BlocksFetched uint64 `json:"blocks_fetched"`
+	ReceivedMoney bool   `json:"received_money"`
+}
+
+// Refresh synchronizes the currently opened wallet with the daemon.
+//
+func (c *Client) Refresh(ctx context.Context) (*RefreshResult, error) {
+	resp := &RefreshResult{}
+	if err := c.rpc.JsonRPC(ctx, "refresh", nil, resp); err != nil {
+		return nil, fmt.Errorf("refresh: %w", err)
+	}
+
+	return resp, nil
+}
+
+// AutoRefresh makes wallet-rpc synchronize the currently opened wallet with
+// the daemon by itself, in the background, every `period` (rounded to
+// seconds).
+//
+func (c *Client) AutoRefresh(ctx context.Context, period time.Duration) error {
+	if err := c.rpc.JsonRPC(ctx, "auto_refresh", map[string]interface{}{
+		"enable": true,
+		"period": uint32(period / time.Second),
+	}, &struct{}{}); err != nil {
+		return fmt.Errorf("auto_refresh: %w", err)
+	}
+
+	return nil
+}
+
+type GetBalanceResult struct {
+	Balance         uint64 `json:"balance"`
+	UnlockedBalance uint64 `json:"unlocked_balance"`
+	BlocksToUnlock  uint64 `json:"blocks_to_unlock"`
+}
+
+// GetBalance retrieves the balance of the first account of the currently
+// opened wallet, in atomic units.
+//
+func (c *Client) GetBalance(ctx context.Context) (*GetBalanceResult, error) {
+	resp := &GetBalanceResult{}
+	if err := c.rpc.JsonRPC(ctx, "get_balance", map[string]interface{}{
+		"account_index": 0,
+	}, resp); err != nil {
+		return nil, fmt.Errorf("get_balance: %w", err)
+	}
+
+	return resp, nil
+}
+
+type Transfer struct {
+	TxID          string `json:"txid"`
+	Address       string `json:"address"`
+	Amount        uint64 `json:"amount"`
+	Confirmations uint64 `json:"confirmations"`
+	Height        uint64 `json:"height"`
+	Timestamp     int64  `json:"timestamp"`
+	Type          string `json:"type"`
+}
+
+type GetTransfersResult struct {
+	In      []Transfer `json:"in"`
+	Pool    []Transfer `json:"pool"`
+	Pending []Transfer `json:"pending"`
+}
+
+// GetIncomingTransfers retrieves the incoming transfers (confirmed or still
+// in the pool) of the currently opened wallet.
+//
+func (c *Client) GetIncomingTransfers(ctx context.Context) (*GetTransfersResult, error) {
+	resp := &GetTransfersResult{}
+	if err := c.rpc.JsonRPC(ctx, "get_transfers", map[string]interface{}{
+		"in":   true,
+		"pool": true,
+	}, resp); err != nil {
+		return nil, fmt.Errorf("get_transfers: %w", err)
+	}
+
+	return resp, nil
+}
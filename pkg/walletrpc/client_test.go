@@ -0,0 +1,47 @@
+package walletrpc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorClassification(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		err          error
+		notOpen      bool
+		fileNotFound bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:    "no wallet opened",
+			err:     errors.New("get_height: rpc error: code=-13 message=No wallet file"),
+			notOpen: true,
+		},
+		{
+			name:         "missing wallet file",
+			err:          errors.New(`open_wallet: rpc error: code=-1 message=file not found "/wallet/view.keys"`),
+			fileNotFound: true,
+		},
+		{
+			name: "wrong password",
+			err:  errors.New("open_wallet: rpc error: code=-1 message=invalid password"),
+		},
+		{
+			name: "other code with the same prefix",
+			err:  errors.New("rpc error: code=-130 message=whatever"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if IsNotOpen(tc.err) != tc.notOpen {
+				t.Errorf("expected IsNotOpen to be %v", tc.notOpen)
+			}
+
+			if IsFileNotFound(tc.err) != tc.fileNotFound {
+				t.Errorf("expected IsFileNotFound to be %v", tc.fileNotFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package walletrpc
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DigestTransport is an http.RoundTripper that authenticates requests using
+// HTTP Digest Authentication (RFC 2617), the scheme used by monerod and
+// monero-wallet-rpc when `--rpc-login` is set.
+//
+type DigestTransport struct {
+	Username string
+	Password string
+
+	// Transport is the underlying RoundTripper used to perform the
+	// requests, defaulting to http.DefaultTransport.
+	//
+	Transport http.RoundTripper
+}
+
+func NewDigestTransport(username, password string) *DigestTransport {
+	return &DigestTransport{
+		Username:  username,
+		Password:  password,
+		Transport: http.DefaultTransport,
+	}
+}
+
+func (t *DigestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	getBody, err := bodyGetter(req)
+	if err != nil {
+		return nil, fmt.Errorf("body getter: %w", err)
+	}
+
+	first, err := cloneRequest(req, getBody)
+	if err != nil {
+		return nil, fmt.Errorf("clone request: %w", err)
+	}
+
+	resp, err := t.Transport.RoundTrip(first)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge, err := findChallenge(resp.Header.Values("Www-Authenticate"))
+	if err != nil {
+		return resp, nil
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	second, err := cloneRequest(req, getBody)
+	if err != nil {
+		return nil, fmt.Errorf("clone request: %w", err)
+	}
+
+	authorization, err := t.authorization(challenge, second.Method, second.URL.RequestURI())
+	if err != nil {
+		return nil, fmt.Errorf("authorization: %w", err)
+	}
+
+	second.Header.Set("Authorization", authorization)
+	return t.Transport.RoundTrip(second)
+}
+
+func (t *DigestTransport) authorization(challenge map[string]string, method, uri string) (string, error) {
+	cnonceBytes := make([]byte, 8)
+	if _, err := rand.Read(cnonceBytes); err != nil {
+		return "", fmt.Errorf("rand read: %w", err)
+	}
+
+	var (
+		cnonce = hex.EncodeToString(cnonceBytes)
+		nc     = "00000001"
+		realm  = challenge["realm"]
+		nonce  = challenge["nonce"]
+		qop    = challenge["qop"]
+		ha1    = md5hex(t.Username + ":" + realm + ":" + t.Password)
+		ha2    = md5hex(method + ":" + uri)
+	)
+
+	var response string
+	if qop == "" {
+		response = md5hex(ha1 + ":" + nonce + ":" + ha2)
+	} else {
+		qop = "auth"
+		response = md5hex(ha1 + ":" + nonce + ":" + nc + ":" + cnonce + ":" + qop + ":" + ha2)
+	}
+
+	fields := []string{
+		fmt.Sprintf(`username="%s"`, t.Username),
+		fmt.Sprintf(`realm="%s"`, realm),
+		fmt.Sprintf(`nonce="%s"`, nonce),
+		fmt.Sprintf(`uri="%s"`, uri),
+		`algorithm=MD5`,
+		fmt.Sprintf(`response="%s"`, response),
+	}
+
+	if qop != "" {
+		fields = append(fields,
+			"qop="+qop,
+			"nc="+nc,
+			fmt.Sprintf(`cnonce="%s"`, cnonce),
+		)
+	}
+
+	if opaque, found := challenge["opaque"]; found {
+		fields = append(fields, fmt.Sprintf(`opaque="%s"`, opaque))
+	}
+
+	return "Digest " + strings.Join(fields, ", "), nil
+}
+
+// findChallenge looks for a Digest challenge that uses plain MD5 (monero
+// offers both MD5 and MD5-sess) and parses its parameters.
+//
+func findChallenge(headers []string) (map[string]string, error) {
+	for _, header := range headers {
+		if !strings.HasPrefix(header, "Digest ") {
+			continue
+		}
+
+		params := parseChallenge(strings.TrimPrefix(header, "Digest "))
+
+		algorithm := params["algorithm"]
+		if algorithm != "" && !strings.EqualFold(algorithm, "MD5") {
+			continue
+		}
+
+		return params, nil
+	}
+
+	return nil, fmt.Errorf("no suitable digest challenge found")
+}
+
+func parseChallenge(s string) map[string]string {
+	params := map[string]string{}
+
+	var (
+		parts   = []string{}
+		current strings.Builder
+		quoted  bool
+	)
+
+	for _, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(c)
+		}
+	}
+	parts = append(parts, current.String())
+
+	for _, part := range parts {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		params[strings.ToLower(kv[0])] = kv[1]
+	}
+
+	// qop may carry a list of options (e.g., "auth,auth-int") - we only
+	// support `auth`.
+	//
+	if qop, found := params["qop"]; found && qop != "" {
+		params["qop"] = "auth"
+	}
+
+	return params
+}
+
+// cloneRequest clones a request, with its body (if any) gotten anew through
+// `getBody`.
+//
+func cloneRequest(req *http.Request, getBody func() (io.ReadCloser, error)) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+
+	if getBody != nil {
+		body, err := getBody()
+		if err != nil {
+			return nil, fmt.Errorf("get body: %w", err)
+		}
+
+		clone.Body = body
+	}
+
+	return clone, nil
+}
+
+// bodyGetter is how the body of a request can be gotten again for each of
+// the times it's sent (once for getting the challenge, once more with the
+// credentials), buffering it if the request doesn't tell how.
+//
+func bodyGetter(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	defer req.Body.Close()
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}, nil
+}
+
+func md5hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
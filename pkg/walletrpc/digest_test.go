@@ -0,0 +1,293 @@
+package walletrpc
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testRealm    = "monero-rpc"
+	testNonce    = "G9ZqXmvx9Nv+dLKz6zGJlg=="
+	testUsername = "user"
+	testPassword = "p4ss,w\"ord"
+)
+
+// digestServer mimics monerod and wallet-rpc's `--rpc-login`: requests
+// without (valid) credentials get challenged with both MD5-sess and MD5.
+//
+type digestServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	bodies []string
+	auths  []map[string]string
+}
+
+func newDigestServer(t *testing.T) *digestServer {
+	t.Helper()
+
+	s := &digestServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *digestServer) handle(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	s.mu.Lock()
+	s.bodies = append(s.bodies, string(body))
+	s.mu.Unlock()
+
+	authorization := req.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Digest ") {
+		for _, algorithm := range []string{"MD5-sess", "MD5"} {
+			w.Header().Add("WWW-Authenticate", fmt.Sprintf(
+				`Digest qop="auth",algorithm=%s,realm="%s",nonce="%s",stale=false`,
+				algorithm, testRealm, testNonce,
+			))
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	params := parseChallenge(strings.TrimPrefix(authorization, "Digest "))
+
+	s.mu.Lock()
+	s.auths = append(s.auths, params)
+	s.mu.Unlock()
+
+	var (
+		ha1      = md5hex(testUsername + ":" + testRealm + ":" + testPassword)
+		ha2      = md5hex(req.Method + ":" + params["uri"])
+		expected = md5hex(ha1 + ":" + testNonce + ":" + params["nc"] + ":" +
+			params["cnonce"] + ":" + params["qop"] + ":" + ha2)
+	)
+
+	if params["response"] != expected {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.Write(body)
+}
+
+func TestDigestTransport(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body func() io.Reader
+	}{
+		{
+			name: "rewindable body",
+			body: func() io.Reader { return strings.NewReader(`{"method":"get_height"}`) },
+		},
+		{
+			name: "one-off body",
+			body: func() io.Reader { return io.MultiReader(strings.NewReader(`{"method":"get_height"}`)) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newDigestServer(t)
+
+			req, err := http.NewRequest("POST", server.URL+"/json_rpc?x=1", tc.body())
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+
+			client := &http.Client{Transport: NewDigestTransport(testUsername, testPassword)}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("do: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected 200, got %d", resp.StatusCode)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != `{"method":"get_height"}` {
+				t.Fatalf("unexpected body '%s'", body)
+			}
+
+			// the body gets sent both for getting challenged and
+			// with the credentials.
+			//
+			expectedBodies := []string{`{"method":"get_height"}`, `{"method":"get_height"}`}
+			if !reflect.DeepEqual(server.bodies, expectedBodies) {
+				t.Fatalf("expected bodies %q, got %q", expectedBodies, server.bodies)
+			}
+
+			auth := server.auths[0]
+			for key, value := range map[string]string{
+				"username":  testUsername,
+				"realm":     testRealm,
+				"nonce":     testNonce,
+				"uri":       "/json_rpc?x=1",
+				"algorithm": "MD5",
+				"qop":       "auth",
+				"nc":        "00000001",
+			} {
+				if auth[key] != value {
+					t.Errorf("%s: expected '%s', got '%s'", key, value, auth[key])
+				}
+			}
+		})
+	}
+}
+
+func TestDigestTransportWrongPassword(t *testing.T) {
+	server := newDigestServer(t)
+
+	client := &http.Client{Transport: NewDigestTransport(testUsername, "wrong")}
+
+	resp, err := client.Post(server.URL+"/json_rpc", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+}
+
+func TestAuthorizationWithoutQop(t *testing.T) {
+	transport := NewDigestTransport("Mufasa", "CircleOfLife")
+
+	// example from RFC 2069, which RFC 2617 keeps compatibility with.
+	//
+	authorization, err := transport.authorization(map[string]string{
+		"realm":  "testrealm@host.com",
+		"nonce":  "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+		"opaque": "5ccc069c403ebaf9f0171e9517f40e41",
+	}, "GET", "/dir/index.html")
+	if err != nil {
+		t.Fatalf("authorization: %v", err)
+	}
+
+	params := parseChallenge(strings.TrimPrefix(authorization, "Digest "))
+
+	if params["response"] != "1949323746fe6a43ef61f9606e7febea" {
+		t.Fatalf("unexpected response '%s'", params["response"])
+	}
+
+	if params["opaque"] != "5ccc069c403ebaf9f0171e9517f40e41" {
+		t.Fatalf("unexpected opaque '%s'", params["opaque"])
+	}
+
+	if _, found := params["qop"]; found {
+		t.Fatalf("unexpected qop in '%s'", authorization)
+	}
+}
+
+func TestFindChallenge(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		headers  []string
+		expected map[string]string
+		err      bool
+	}{
+		{
+			name: "monero picks md5 over md5-sess",
+			headers: []string{
+				`Digest qop="auth",algorithm=MD5-sess,realm="monero-rpc",nonce="abc",stale=false`,
+				`Digest qop="auth",algorithm=MD5,realm="monero-rpc",nonce="abc",stale=false`,
+			},
+			expected: map[string]string{
+				"qop":       "auth",
+				"algorithm": "MD5",
+				"realm":     "monero-rpc",
+				"nonce":     "abc",
+				"stale":     "false",
+			},
+		},
+		{
+			name:    "no algorithm means md5",
+			headers: []string{`Digest realm="r", nonce="n"`},
+			expected: map[string]string{
+				"realm": "r",
+				"nonce": "n",
+			},
+		},
+		{
+			name:    "only md5-sess",
+			headers: []string{`Digest qop="auth",algorithm=MD5-sess,realm="r",nonce="n"`},
+			err:     true,
+		},
+		{
+			name:    "basic only",
+			headers: []string{`Basic realm="r"`},
+			err:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params, err := findChallenge(tc.headers)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %v", params)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("find challenge: %v", err)
+			}
+
+			if !reflect.DeepEqual(params, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, params)
+			}
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    string
+		expected map[string]string
+	}{
+		{
+			name:  "quoted commas",
+			input: `Realm="a, b",nonce="n,n"`,
+			expected: map[string]string{
+				"realm": "a, b",
+				"nonce": "n,n",
+			},
+		},
+		{
+			name:  "qop list narrowed to auth",
+			input: `qop="auth,auth-int", nonce=n`,
+			expected: map[string]string{
+				"qop":   "auth",
+				"nonce": "n",
+			},
+		},
+		{
+			name:  "values with equal signs",
+			input: `nonce="G9ZqXmvx9Nv+dLKz6zGJlg==", garbage`,
+			expected: map[string]string{
+				"nonce": "G9ZqXmvx9Nv+dLKz6zGJlg==",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params := parseChallenge(tc.input)
+			if !reflect.DeepEqual(params, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, params)
+			}
+		})
+	}
+}
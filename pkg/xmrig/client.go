@@ -0,0 +1,207 @@
+package xmrig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Client is a thin client for xmrig's HTTP API.
+//
+type Client struct {
+	address     string
+	accessToken string
+	httpClient  *http.Client
+}
+
+type ClientOption func(c *Client)
+
+// WithAccessToken makes the client authenticate against an xmrig HTTP API
+// configured with an `access-token`.
+//
+func WithAccessToken(token string) ClientOption {
+	return func(c *Client) {
+		c.accessToken = token
+	}
+}
+
+// NewClient instantiates a client targetting the xmrig HTTP API at
+// `address` (e.g., `http://10.0.0.1:8080`).
+//
+func NewClient(address string, opts ...ClientOption) *Client {
+	c := &Client{
+		address: address,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+type SummaryHashrate struct {
+	// Total holds the hashrate (H/s) over the last 10 seconds, 60 seconds
+	// and 15 minutes, in that order. Entries are null when xmrig hasn't
+	// been running for long enough.
+	//
+	Total   []*float64 `json:"total"`
+	Highest *float64   `json:"highest"`
+}
+
+type SummaryConnection struct {
+	Pool     string `json:"pool"`
+	Uptime   int64  `json:"uptime"`
+	Ping     int64  `json:"ping"`
+	Failures int64  `json:"failures"`
+	Accepted uint64 `json:"accepted"`
+	Rejected uint64 `json:"rejected"`
+}
+
+type SummaryResults struct {
+	DiffCurrent uint64 `json:"diff_current"`
+	SharesGood  uint64 `json:"shares_good"`
+	SharesTotal uint64 `json:"shares_total"`
+	HashesTotal uint64 `json:"hashes_total"`
+}
+
+type SummaryCPU struct {
+	Brand string `json:"brand"`
+
+	// MSR is the MSR preset that applies to the CPU (e.g., `intel`,
+	// `ryzen_19h`, or `none`).
+	//
+	MSR string `json:"msr"`
+}
+
+type Summary struct {
+	ID         string            `json:"id"`
+	WorkerID   string            `json:"worker_id"`
+	Version    string            `json:"version"`
+	Uptime     int64             `json:"uptime"`
+	Hashrate   SummaryHashrate   `json:"hashrate"`
+	Results    SummaryResults    `json:"results"`
+	Connection SummaryConnection `json:"connection"`
+	CPU        SummaryCPU        `json:"cpu"`
+
+	// HugePages is either `[allocated, total]` or, in older versions of
+	// xmrig, a boolean telling whether they're being used at all.
+	//
+	HugePages json.RawMessage `json:"hugepages"`
+}
+
+// HugePagesUsage retrieves how many huge pages xmrig managed to allocate out
+// of those that it tried to.
+//
+func (s *Summary) HugePagesUsage() (uint64, uint64) {
+	var pages []uint64
+	if err := json.Unmarshal(s.HugePages, &pages); err == nil && len(pages) == 2 {
+		return pages[0], pages[1]
+	}
+
+	return 0, 0
+}
+
+// HashrateAt retrieves the hashrate for the window at index `idx` of the
+// total hashrates (0 for 10s, 1 for 60s, 2 for 15m), being zero if not
+// available.
+//
+func (s *Summary) HashrateAt(idx int) float64 {
+	if idx >= len(s.Hashrate.Total) || s.Hashrate.Total[idx] == nil {
+		return 0
+	}
+
+	return *s.Hashrate.Total[idx]
+}
+
+// Summary retrieves the overall summary of the miner (`/2/summary`).
+//
+func (c *Client) Summary(ctx context.Context) (*Summary, error) {
+	resp := &Summary{}
+	if err := c.get(ctx, "/2/summary", resp); err != nil {
+		return nil, fmt.Errorf("get summary: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.address+path, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	return nil
+}
+
+type ProxySummaryMiners struct {
+	Now uint64 `json:"now"`
+	Max uint64 `json:"max"`
+}
+
+type ProxySummaryUpstreams struct {
+	Active uint64  `json:"active"`
+	Sleep  uint64  `json:"sleep"`
+	Error  uint64  `json:"error"`
+	Total  uint64  `json:"total"`
+	Ratio  float64 `json:"ratio"`
+}
+
+type ProxySummaryResults struct {
+	Accepted    uint64 `json:"accepted"`
+	Rejected    uint64 `json:"rejected"`
+	Invalid     uint64 `json:"invalid"`
+	Expired     uint64 `json:"expired"`
+	HashesTotal uint64 `json:"hashes_total"`
+}
+
+// ProxySummary is the summary that xmrig-proxy reports about the miners
+// connected to it and its connections to the pools.
+//
+type ProxySummary struct {
+	ID        string                `json:"id"`
+	WorkerID  string                `json:"worker_id"`
+	Version   string                `json:"version"`
+	Mode      string                `json:"mode"`
+	Uptime    int64                 `json:"uptime"`
+	Miners    ProxySummaryMiners    `json:"miners"`
+	Workers   uint64                `json:"workers"`
+	Upstreams ProxySummaryUpstreams `json:"upstreams"`
+	Results   ProxySummaryResults   `json:"results"`
+}
+
+// ProxySummary retrieves the overall summary of an xmrig-proxy
+// (`/1/summary`).
+//
+func (c *Client) ProxySummary(ctx context.Context) (*ProxySummary, error) {
+	resp := &ProxySummary{}
+	if err := c.get(ctx, "/1/summary", resp); err != nil {
+		return nil, fmt.Errorf("get proxy summary: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,140 @@
+package xmrig
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testAccessToken = "s3cr3t"
+
+// newTestServer mimics xmrig's HTTP API (with an `access-token`), replying
+// to `/2/summary` with the contents of `fixture`.
+//
+func newTestServer(t *testing.T, fixture string) *httptest.Server {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+testAccessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if req.URL.Path != "/2/summary" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func summary(t *testing.T, fixture string) *Summary {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(newTestServer(t, fixture).URL, WithAccessToken(testAccessToken))
+
+	s, err := client.Summary(ctx)
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+
+	return s
+}
+
+func TestSummary(t *testing.T) {
+	s := summary(t, "summary.json")
+
+	if s.WorkerID != "miners-0" || s.Version != "6.18.1" || s.Uptime != 7214 {
+		t.Fatalf("unexpected identification %q %q %d", s.WorkerID, s.Version, s.Uptime)
+	}
+
+	for idx, expected := range []float64{3512.4, 3498.71, 3501.02, 0} {
+		if hashrate := s.HashrateAt(idx); hashrate != expected {
+			t.Errorf("hashrate %d: expected %v, got %v", idx, expected, hashrate)
+		}
+	}
+
+	if s.Hashrate.Highest == nil || *s.Hashrate.Highest != 3590.3 {
+		t.Errorf("unexpected highest hashrate %v", s.Hashrate.Highest)
+	}
+
+	expectedResults := SummaryResults{
+		DiffCurrent: 120001,
+		SharesGood:  211,
+		SharesTotal: 213,
+		HashesTotal: 25324310,
+	}
+	if s.Results != expectedResults {
+		t.Errorf("expected results %+v, got %+v", expectedResults, s.Results)
+	}
+
+	expectedConnection := SummaryConnection{
+		Pool:     "pool.supportxmr.com:443",
+		Uptime:   7182,
+		Ping:     41,
+		Failures: 1,
+		Accepted: 211,
+		Rejected: 2,
+	}
+	if s.Connection != expectedConnection {
+		t.Errorf("expected connection %+v, got %+v", expectedConnection, s.Connection)
+	}
+
+	expectedCPU := SummaryCPU{
+		Brand: "AMD Ryzen 5 3600 6-Core Processor",
+		MSR:   "ryzen_17h",
+	}
+	if s.CPU != expectedCPU {
+		t.Errorf("expected cpu %+v, got %+v", expectedCPU, s.CPU)
+	}
+
+	if allocated, total := s.HugePagesUsage(); allocated != 1168 || total != 1168 {
+		t.Errorf("unexpected huge pages %d/%d", allocated, total)
+	}
+}
+
+func TestSummaryStarting(t *testing.T) {
+	s := summary(t, "summary-starting.json")
+
+	for idx := 0; idx < 3; idx++ {
+		if hashrate := s.HashrateAt(idx); hashrate != 0 {
+			t.Errorf("hashrate %d: expected 0, got %v", idx, hashrate)
+		}
+	}
+
+	if s.Hashrate.Highest != nil {
+		t.Errorf("expected no highest hashrate, got %v", *s.Hashrate.Highest)
+	}
+
+	// older versions only tell whether huge pages are used at all.
+	//
+	if allocated, total := s.HugePagesUsage(); allocated != 0 || total != 0 {
+		t.Errorf("unexpected huge pages %d/%d", allocated, total)
+	}
+}
+
+func TestSummaryUnauthorized(t *testing.T) {
+	server := newTestServer(t, "summary.json")
+
+	if _, err := NewClient(server.URL).Summary(context.Background()); err == nil {
+		t.Fatal("expected error without the access token")
+	}
+}